@@ -0,0 +1,136 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// ValueCount represents the number of APIs with a particular field value.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Aggregation represents the result of aggregating field values across the
+// catalog.
+type Aggregation struct {
+	Values       []ValueCount `json:"values"`
+	PagesScanned int          `json:"pages_scanned"`
+	Truncated    bool         `json:"truncated,omitempty"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// ListAPITypesParams represents the parameters for the listAPITypes tool.
+type ListAPITypesParams struct{}
+
+// createListAPITypesTool creates a tool for listing the distinct API types in
+// the catalog.
+func createListAPITypesTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListAPITypesParams]{
+		Name: "list_api_types",
+		Description: "List the distinct API types (protocols) in the catalog with the number of APIs per type, " +
+			"sorted by count. Use these values for the `type` filter of advanced_list_apis.",
+		HandleFunc: func(ctx context.Context, params ListAPITypesParams) *mcp.CallToolResult {
+			agg, err := aggregateAPIs(ctx, func(rec map[string]any) []string {
+				if t := apiType(rec); t != "" {
+					return []string{t}
+				}
+				return nil
+			})
+			if err != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", err)
+			}
+
+			result, err := marshalResult(agg, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// ListCategoriesParams represents the parameters for the listCategories tool.
+type ListCategoriesParams struct{}
+
+// createListCategoriesTool creates a tool for listing the distinct categories
+// of APIs in the catalog.
+func createListCategoriesTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListCategoriesParams]{
+		Name: "list_categories",
+		Description: "List the distinct categories of APIs in the catalog with the number of APIs per category, " +
+			"sorted by count. Use these values for the `category` filter of list_apis.",
+		HandleFunc: func(ctx context.Context, params ListCategoriesParams) *mcp.CallToolResult {
+			agg, err := aggregateAPIs(ctx, apiCategories)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", err)
+			}
+
+			result, err := marshalResult(agg, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// aggregateAPIs walks the catalog (bounded by the page budget) and counts the
+// values returned by valuesFn for each API. Values are sorted by descending
+// count, then by value. If a page times out, the partial aggregation is
+// returned with its Error field set.
+func aggregateAPIs(ctx context.Context, valuesFn func(rec map[string]any) []string) (Aggregation, error) {
+	counts := make(map[string]int)
+
+	pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+		var rec map[string]any
+		if err := json.Unmarshal(item, &rec); err != nil {
+			return err
+		}
+		for _, v := range valuesFn(rec) {
+			counts[v]++
+		}
+		return nil
+	})
+
+	agg := Aggregation{
+		Values:       make([]ValueCount, 0, len(counts)),
+		PagesScanned: pages,
+		Truncated:    more,
+	}
+	if err != nil {
+		if !errors.Is(err, errPageTimeout) {
+			return Aggregation{}, err
+		}
+		agg.Error = err.Error()
+		agg.Truncated = true
+	}
+
+	for v, n := range counts {
+		agg.Values = append(agg.Values, ValueCount{Value: v, Count: n})
+	}
+	slices.SortFunc(agg.Values, func(a, b ValueCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Value, b.Value))
+	})
+
+	return agg, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListAPITypes(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis": {body: `[
+			{"id":"a","api_type":"rest_json"},
+			{"id":"b","type":"graphql"},
+			{"id":"c","api_type":"soap_xml"},
+			{"id":"d","api_type":"rest_json"},
+			{"id":"e"},
+			{"id":"f","api_type":"graphql"},
+			{"id":"g","api_type":"rest_json"}
+		]`},
+	})
+
+	res := callTool(t, createListAPITypesTool(), `{}`)
+
+	var got Aggregation
+	decodeResult(t, res, &got)
+
+	// Sorted by descending count, ties by value.
+	want := []ValueCount{
+		{Value: "rest_json", Count: 3},
+		{Value: "graphql", Count: 2},
+		{Value: "soap_xml", Count: 1},
+	}
+	if !reflect.DeepEqual(got.Values, want) {
+		t.Errorf("values = %+v, want %+v", got.Values, want)
+	}
+	if got.PagesScanned != 1 || got.Truncated {
+		t.Errorf("pages scanned = %d, truncated = %v; want 1 page", got.PagesScanned, got.Truncated)
+	}
+}
+
+func TestListCategories(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis": {body: `[
+			{"id":"a","categories":["Wonen","Verkeer"]},
+			{"id":"b","categories":[{"name":"Verkeer"},"Verkeer"]},
+			{"id":"c","category":"Belastingen"},
+			{"id":"d","categories":[{"label":"Wonen"}," Zorg "]},
+			{"id":"e"}
+		]`},
+	})
+
+	res := callTool(t, createListCategoriesTool(), `{}`)
+
+	var got Aggregation
+	decodeResult(t, res, &got)
+
+	// Categories are counted once per API; ties are sorted by value.
+	want := []ValueCount{
+		{Value: "Verkeer", Count: 2},
+		{Value: "Wonen", Count: 2},
+		{Value: "Belastingen", Count: 1},
+		{Value: "Zorg", Count: 1},
+	}
+	if !reflect.DeepEqual(got.Values, want) {
+		t.Errorf("values = %+v, want %+v", got.Values, want)
+	}
+}
+
+func TestListAPIsCategoryFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		wantQuery string
+	}{
+		{"no filter", `{}`, "page=1"},
+		{"category", `{"category":"Wonen"}`, "category=Wonen&page=1"},
+		{"trimmed", `{"category":"  Verkeer en vervoer "}`, "category=Verkeer+en+vervoer&page=1"},
+		{"blank", `{"category":"  "}`, "page=1"},
+		{"with paging", `{"category":"Zorg","page":2,"perPage":5}`, "category=Zorg&page=2&perPage=5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests := newFixtureServer(t, map[string]fixture{"/apis": {body: `[]`}})
+
+			res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), tt.args)
+			if res.IsError {
+				t.Fatalf("unexpected error result: %v", resultText(t, res))
+			}
+			if got := (*requests)[0].URL.RawQuery; got != tt.wantQuery {
+				t.Errorf("query = %q, want %q", got, tt.wantQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// DiffAPIsParams represents the parameters for the diffAPIs tool.
+// The `idA` and `idB` parameters are required.
+type DiffAPIsParams struct {
+	IDA string `json:"idA" jsonschema:"required" jsonschema_description:"ID of the first API."`
+	IDB string `json:"idB" jsonschema:"required" jsonschema_description:"ID of the second API."`
+}
+
+// DiffAPIsResponse represents the response from the diffAPIs tool. Fields are
+// keyed by their path, with the keys of nested objects joined by dots (e.g.
+// "contact.email").
+type DiffAPIsResponse struct {
+	IDA     string                 `json:"id_a"`
+	IDB     string                 `json:"id_b"`
+	Equal   bool                   `json:"equal"`
+	Added   map[string]any         `json:"added,omitempty"`
+	Removed map[string]any         `json:"removed,omitempty"`
+	Changed map[string]FieldChange `json:"changed,omitempty"`
+}
+
+// FieldChange represents a field with a different value in both APIs.
+type FieldChange struct {
+	A any `json:"a"`
+	B any `json:"b"`
+}
+
+// createDiffAPIsTool creates a tool for comparing two APIs.
+func createDiffAPIsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[DiffAPIsParams]{
+		Name: "diff_apis",
+		Description: "Compare two APIs by ID (e.g. two versions of an API) and return how their fields differ: " +
+			"fields only in the second API are `added`, fields only in the first are `removed`, and fields with " +
+			"different values are `changed`. Nested objects are compared field by field, lists as a whole.",
+		HandleFunc: func(ctx context.Context, params DiffAPIsParams) *mcp.CallToolResult {
+			ids := []string{strings.TrimSpace(params.IDA), strings.TrimSpace(params.IDB)}
+			if ids[0] == "" || ids[1] == "" {
+				return newToolCallErrorResult("Missing idA or idB")
+			}
+
+			apis, errs := fetchConcurrent(ctx, ids, fetchAPI)
+			for i, err := range errs {
+				if err != nil {
+					return newToolCallErrorResult("Error fetching API %q: %v", ids[i], err)
+				}
+			}
+
+			response := DiffAPIsResponse{
+				IDA:     ids[0],
+				IDB:     ids[1],
+				Added:   make(map[string]any),
+				Removed: make(map[string]any),
+				Changed: make(map[string]FieldChange),
+			}
+			diffRecords("", apis[0], apis[1], &response)
+			response.Equal = len(response.Added) == 0 && len(response.Removed) == 0 && len(response.Changed) == 0
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// diffRecords records the differences between decoded JSON objects a and b in
+// diff, recursing into fields that are objects in both. Field paths are
+// prefixed with prefix.
+func diffRecords(prefix string, a, b map[string]any, diff *DiffAPIsResponse) {
+	for key, va := range a {
+		path := prefix + key
+		vb, ok := b[key]
+		if !ok {
+			diff.Removed[path] = va
+			continue
+		}
+
+		objA, okA := va.(map[string]any)
+		objB, okB := vb.(map[string]any)
+		if okA && okB {
+			diffRecords(path+".", objA, objB, diff)
+			continue
+		}
+
+		if !reflect.DeepEqual(va, vb) {
+			diff.Changed[path] = FieldChange{A: va, B: vb}
+		}
+	}
+
+	for key, vb := range b {
+		if _, ok := a[key]; !ok {
+			diff.Added[prefix+key] = vb
+		}
+	}
+}
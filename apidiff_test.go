@@ -0,0 +1,67 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffAPIs(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis/v1": {body: `{
+			"id": "v1",
+			"title": "Test API",
+			"description": "First version",
+			"contact": {"name": "Team", "email": "team@example.com"},
+			"environments": [{"name": "production"}],
+			"deprecated": true
+		}`},
+		"/apis/v2": {body: `{
+			"id": "v2",
+			"title": "Test API",
+			"description": "Second version",
+			"contact": {"name": "Team", "email": "api@example.com", "url": "https://example.com"},
+			"environments": [{"name": "production"}, {"name": "acceptance"}]
+		}`},
+	})
+
+	t.Run("different APIs", func(t *testing.T) {
+		var got DiffAPIsResponse
+		decodeResult(t, callTool(t, createDiffAPIsTool(), `{"idA":"v1","idB":"v2"}`), &got)
+
+		want := DiffAPIsResponse{
+			IDA:     "v1",
+			IDB:     "v2",
+			Added:   map[string]any{"contact.url": "https://example.com"},
+			Removed: map[string]any{"deprecated": true},
+			Changed: map[string]FieldChange{
+				"id":            {A: "v1", B: "v2"},
+				"description":   {A: "First version", B: "Second version"},
+				"contact.email": {A: "team@example.com", B: "api@example.com"},
+				"environments": {
+					A: []any{map[string]any{"name": "production"}},
+					B: []any{map[string]any{"name": "production"}, map[string]any{"name": "acceptance"}},
+				},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("diff = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("same API", func(t *testing.T) {
+		var got DiffAPIsResponse
+		decodeResult(t, callTool(t, createDiffAPIsTool(), `{"idA":"v1","idB":"v1"}`), &got)
+
+		if !got.Equal || len(got.Added) > 0 || len(got.Removed) > 0 || len(got.Changed) > 0 {
+			t.Errorf("diff = %+v, want equal APIs", got)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		expectError(t, callTool(t, createDiffAPIsTool(), `{"idA":"v1","idB":"v3"}`), `Error fetching API "v3"`)
+	})
+
+	t.Run("missing ID", func(t *testing.T) {
+		expectError(t, callTool(t, createDiffAPIsTool(), `{"idA":"v1","idB":" "}`), "Missing idA or idB")
+	})
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// expandArgFiles replaces every `@file` argument with the arguments read from
+// that file. Arguments after a `--` terminator are left untouched. Argument
+// files aren't expanded recursively.
+func expandArgFiles(args []string) ([]string, error) {
+	var expanded []string
+
+	for i, arg := range args {
+		if arg == "--" {
+			expanded = append(expanded, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			expanded = append(expanded, arg)
+			continue
+		}
+
+		data, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		words, err := splitShellWords(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", arg[1:], err)
+		}
+		expanded = append(expanded, words...)
+	}
+
+	return expanded, nil
+}
+
+// splitShellWords splits s into words using shell-like rules: words are
+// separated by whitespace, single quotes preserve everything literally, double
+// quotes allow backslash escapes, and lines starting with `#` are comments.
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case r == '#' && !inWord:
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
+
+// Environment variables for flags whose name doesn't follow the `MCP_<FLAG>`
+// convention (see envVarName).
+var flagEnvVars = map[string]string{
+	"http": "MCP_HTTP_ADDR",
+}
+
+// Flags that can't be set with an environment variable.
+var flagsWithoutEnv = []string{"version"}
+
+// envVarName returns the environment variable a flag falls back to: the flag
+// name uppercased, with dashes replaced by underscores and prefixed by `MCP_`
+// (e.g. `MCP_API_BASE_URL` for `-api-base-url`).
+func envVarName(flagName string) string {
+	if name, ok := flagEnvVars[flagName]; ok {
+		return name
+	}
+	return "MCP_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyEnvFlags sets every flag of fs that wasn't set explicitly on the
+// command line from its environment variable, if that's set. An explicit
+// flag takes precedence over an environment variable, which takes precedence
+// over the flag's default. Boolean flags accept `1/true/yes` and `0/false/no`.
+func applyEnvFlags(fs *flag.FlagSet, lookupEnv func(string) (string, bool)) error {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || explicit[f.Name] || slices.Contains(flagsWithoutEnv, f.Name) {
+			return
+		}
+
+		name := envVarName(f.Name)
+		value, ok := lookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+			switch strings.ToLower(strings.TrimSpace(value)) {
+			case "1", "true", "yes":
+				value = "true"
+			case "0", "false", "no", "":
+				value = "false"
+			default:
+				err = fmt.Errorf("invalid boolean value %q for %v", value, name)
+				return
+			}
+		}
+
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %v: %w", value, name, setErr)
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestApplyEnvFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		env       map[string]string
+		wantURL   string
+		wantHTTP  string
+		wantDebug bool
+		noStdio   bool
+		wantErr   bool
+	}{
+		{name: "default", wantURL: "https://default", wantHTTP: ":8080"},
+		{
+			name:    "environment beats default",
+			env:     map[string]string{"MCP_API_BASE_URL": "https://env", "MCP_HTTP_ADDR": ":9090", "MCP_DEBUG": "yes"},
+			wantURL: "https://env", wantHTTP: ":9090", wantDebug: true,
+		},
+		{
+			name:    "flag beats environment",
+			args:    []string{"-api-base-url", "https://flag", "-debug=false"},
+			env:     map[string]string{"MCP_API_BASE_URL": "https://env", "MCP_DEBUG": "1"},
+			wantURL: "https://flag", wantHTTP: ":8080",
+		},
+		{
+			name:    "boolean false",
+			env:     map[string]string{"MCP_STDIO": " No "},
+			wantURL: "https://default", wantHTTP: ":8080", noStdio: true,
+		},
+		{
+			name:    "version isn't read from the environment",
+			env:     map[string]string{"MCP_VERSION": "true"},
+			wantURL: "https://default", wantHTTP: ":8080",
+		},
+		{name: "invalid boolean", env: map[string]string{"MCP_DEBUG": "maybe"}, wantErr: true},
+		{name: "invalid value", env: map[string]string{"MCP_RETRIES": "many"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			baseURL := fs.String("api-base-url", "https://default", "")
+			httpAddr := fs.String("http", ":8080", "")
+			debug := fs.Bool("debug", false, "")
+			stdio := fs.Bool("stdio", true, "")
+			version := fs.Bool("version", false, "")
+			fs.Int("retries", 3, "")
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			err := applyEnvFlags(fs, func(name string) (string, bool) {
+				v, ok := tt.env[name]
+				return v, ok
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *baseURL != tt.wantURL || *httpAddr != tt.wantHTTP || *debug != tt.wantDebug {
+				t.Errorf("got api-base-url %q, http %q, debug %v; want %q, %q, %v",
+					*baseURL, *httpAddr, *debug, tt.wantURL, tt.wantHTTP, tt.wantDebug)
+			}
+			if *stdio == tt.noStdio {
+				t.Errorf("stdio = %v, want %v", *stdio, !tt.noStdio)
+			}
+			if *version {
+				t.Error("version = true, want it not set from the environment")
+			}
+		})
+	}
+}
+
+func TestEnvVarName(t *testing.T) {
+	tests := map[string]string{
+		"api-base-url": "MCP_API_BASE_URL",
+		"stdio":        "MCP_STDIO",
+		"http":         "MCP_HTTP_ADDR",
+	}
+	for flagName, want := range tests {
+		if got := envVarName(flagName); got != want {
+			t.Errorf("envVarName(%q) = %q, want %q", flagName, got, want)
+		}
+	}
+}
+
+func TestExpandArgFiles(t *testing.T) {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	content := `# Connection settings
+-api-base-url https://api.example/v1 # trailing comment
+-header 'X-Api-Key: abc def'
+-header "X-Client: \"quoted\" value"
+
+-tools list_apis,get_api
+`
+	if err := os.WriteFile(argsFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "expanded in place",
+			args: []string{"-stdio", "@" + argsFile, "-debug"},
+			want: []string{
+				"-stdio",
+				"-api-base-url", "https://api.example/v1",
+				"-header", "X-Api-Key: abc def",
+				"-header", `X-Client: "quoted" value`,
+				"-tools", "list_apis,get_api",
+				"-debug",
+			},
+		},
+		{
+			name: "not after terminator",
+			args: []string{"-stdio", "--", "@" + argsFile},
+			want: []string{"-stdio", "--", "@" + argsFile},
+		},
+		{name: "lone at sign", args: []string{"@"}, want: []string{"@"}},
+		{name: "missing file", args: []string{"@" + filepath.Join(dir, "missing")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandArgFiles(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expandArgFiles(%q) succeeded, want an error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandArgFiles(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    []string
+		wantErr bool
+	}{
+		{s: "a  b\tc\r\nd", want: []string{"a", "b", "c", "d"}},
+		{s: `'single # "quoted"' "double 'quoted'"`, want: []string{`single # "quoted"`, `double 'quoted'`}},
+		{s: `"a \"b\" \\c"`, want: []string{`a "b" \c`}},
+		{s: `escaped\ space`, want: []string{"escaped space"}},
+		{s: "a#b # comment\nc", want: []string{"a#b", "c"}},
+		{s: `''`, want: []string{""}},
+		{s: "# only a comment", want: nil},
+		{s: `"unterminated`, wantErr: true},
+		{s: `'unterminated`, wantErr: true},
+		{s: `trailing\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitShellWords(tt.s)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitShellWords(%q) succeeded, want an error", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitShellWords(%q): %v", tt.s, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitShellWords(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
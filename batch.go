@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Maximum number of IDs per call of the getAPIsBatch tool.
+const maxBatchIDs = 50
+
+// GetAPIsBatchParams represents the parameters for the getAPIsBatch tool.
+// The `ids` parameter is required. It's a comma-separated string rather than
+// an array, because go-mcp can't validate array parameters.
+type GetAPIsBatchParams struct {
+	IDs string `json:"ids" jsonschema:"required" jsonschema_description:"Comma-separated IDs of the APIs, at most 50. Duplicates are ignored."`
+}
+
+// GetAPIsBatchResponse represents the response from the getAPIsBatch tool.
+type GetAPIsBatchResponse struct {
+	APIs   map[string]json.RawMessage `json:"apis"`
+	Errors map[string]string          `json:"errors,omitempty"`
+}
+
+// createGetAPIsBatchTool creates a tool for getting multiple APIs by ID in a
+// single call.
+func createGetAPIsBatchTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPIsBatchParams]{
+		Name: "get_apis_batch",
+		Description: "Get multiple APIs by ID (comma-separated, at most 50) in a single call. Results are keyed by ID; " +
+			"APIs that can't be fetched (e.g. because they don't exist) are reported in `errors` instead of failing the batch.",
+		HandleFunc: func(ctx context.Context, params GetAPIsBatchParams) *mcp.CallToolResult {
+			var ids []string
+			for _, id := range splitList(params.IDs) {
+				if !slices.Contains(ids, id) {
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) == 0 {
+				return newToolCallErrorResult("Missing ids")
+			}
+			if len(ids) > maxBatchIDs {
+				return newToolCallErrorResult("Too many ids: %d, must be at most %d", len(ids), maxBatchIDs)
+			}
+
+			apis, errs := fetchConcurrent(ctx, ids, func(ctx context.Context, id string) (json.RawMessage, error) {
+				api, err := fetchAPIRaw(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				return normalizeRecords(api)
+			})
+			if ctx.Err() != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", ctx.Err())
+			}
+
+			response := GetAPIsBatchResponse{
+				APIs: make(map[string]json.RawMessage),
+			}
+			for i, id := range ids {
+				if errs[i] != nil {
+					if response.Errors == nil {
+						response.Errors = make(map[string]string)
+					}
+					response.Errors[id] = errs[i].Error()
+					continue
+				}
+				response.APIs[id] = apis[i]
+			}
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// splitList splits a comma-separated list parameter into its trimmed,
+// non-empty values.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGetAPIsBatch(t *testing.T) {
+	t.Run("success with errors", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/apis/a": {body: `{"id":"a"}`},
+			"/apis/b": {body: `{"id":"b"}`},
+		})
+
+		res := callTool(t, createGetAPIsBatchTool(), `{"ids":" a, b,a,,missing "}`)
+
+		var got struct {
+			APIs   map[string]json.RawMessage `json:"apis"`
+			Errors map[string]string          `json:"errors"`
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.APIs) != 2 || got.APIs["a"] == nil || got.APIs["b"] == nil {
+			t.Errorf("apis = %v, want a and b", got.APIs)
+		}
+		if len(got.Errors) != 1 || !strings.Contains(got.Errors["missing"], "not found") {
+			t.Errorf("errors = %v, want missing not found", got.Errors)
+		}
+		// Duplicate IDs are fetched once.
+		if len(*requests) != 3 {
+			t.Errorf("got %d upstream requests, want 3", len(*requests))
+		}
+	})
+
+	t.Run("missing ids", func(t *testing.T) {
+		res := callTool(t, createGetAPIsBatchTool(), `{"ids":" , "}`)
+		expectError(t, res, "Missing ids")
+	})
+
+	t.Run("too many ids", func(t *testing.T) {
+		ids := make([]string, maxBatchIDs+1)
+		for i := range ids {
+			ids[i] = fmt.Sprint(i)
+		}
+
+		res := callTool(t, createGetAPIsBatchTool(), fmt.Sprintf(`{"ids":%q}`, strings.Join(ids, ",")))
+		expectError(t, res, "Too many ids")
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {status: http.StatusInternalServerError, body: `{"message":"boom"}`},
+		})
+
+		res := callTool(t, createGetAPIsBatchTool(), `{"ids":"a"}`)
+
+		var got struct {
+			Errors map[string]string `json:"errors"`
+		}
+		decodeResult(t, res, &got)
+		if !strings.Contains(got.Errors["a"], "boom") {
+			t.Errorf("errors = %v, want the upstream error for a", got.Errors)
+		}
+	})
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{"", nil},
+		{" , ,", nil},
+		{"a", []string{"a"}},
+		{" a ,b,, c ", []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitList(tt.s); !slices.Equal(got, tt.want) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
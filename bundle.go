@@ -0,0 +1,175 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Limits for the catalog bundle: the maximum number of API entries and the
+// maximum total size of the (uncompressed) entries.
+const (
+	maxBundleEntries = 1000
+	maxBundleBytes   = 8 << 20
+)
+
+// Characters that aren't allowed in bundle file names.
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExportCatalogBundleParams represents the parameters for the exportCatalogBundle tool.
+type ExportCatalogBundleParams struct{}
+
+// ExportCatalogBundleResponse represents the response from the exportCatalogBundle tool.
+type ExportCatalogBundleResponse struct {
+	Bundle       string `json:"bundle"`
+	Encoding     string `json:"encoding"`
+	Entries      int    `json:"entries"`
+	Bytes        int    `json:"bytes"`
+	PagesScanned int    `json:"pages_scanned"`
+	Truncated    bool   `json:"truncated,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// BundleIndexEntry represents an API in the `index.json` file of a catalog
+// bundle.
+type BundleIndexEntry struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	File string `json:"file"`
+}
+
+// createExportCatalogBundleTool creates a tool for exporting the catalog as a
+// zip bundle.
+func createExportCatalogBundleTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ExportCatalogBundleParams]{
+		Name: "export_catalog_bundle",
+		Description: "Export the catalog as a base64-encoded zip bundle for offline archival. The bundle contains " +
+			"one JSON file per API (`apis/<id>.json`) and an `index.json` listing them. The number of entries " +
+			"and the total size are capped; `truncated` is set when the bundle doesn't hold the whole catalog.",
+		HandleFunc: func(ctx context.Context, params ExportCatalogBundleParams) *mcp.CallToolResult {
+			response, err := exportCatalogBundle(ctx)
+			if err != nil {
+				return newToolCallErrorResult("Error exporting catalog: %v", err)
+			}
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// exportCatalogBundle walks the catalog (bounded by the page budget and the
+// bundle limits) and writes it to a zip archive. If a page times out, the
+// partial bundle is returned with its Error field set.
+func exportCatalogBundle(ctx context.Context) (ExportCatalogBundleResponse, error) {
+	var (
+		buf     bytes.Buffer
+		index   []BundleIndexEntry
+		size    int
+		limited bool
+	)
+	zw := zip.NewWriter(&buf)
+	files := make(map[string]bool)
+
+	pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+		var rec map[string]any
+		if err := json.Unmarshal(item, &rec); err != nil {
+			return err
+		}
+
+		var data bytes.Buffer
+		if err := json.Indent(&data, item, "", "  "); err != nil {
+			return err
+		}
+		if len(index) >= maxBundleEntries || size+data.Len() > maxBundleBytes {
+			limited = true
+			return errStopWalk
+		}
+
+		id := apiID(rec)
+		file := "apis/" + bundleFileName(id, len(index), files) + ".json"
+		w, err := zw.Create(file)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data.Bytes()); err != nil {
+			return err
+		}
+
+		size += data.Len()
+		index = append(index, BundleIndexEntry{ID: id, Name: apiName(rec), File: file})
+		return nil
+	})
+
+	response := ExportCatalogBundleResponse{
+		Encoding:     "base64",
+		Entries:      len(index),
+		PagesScanned: pages,
+		Truncated:    more || limited,
+	}
+	if err != nil {
+		if !errors.Is(err, errPageTimeout) {
+			return ExportCatalogBundleResponse{}, err
+		}
+		response.Error = err.Error()
+		response.Truncated = true
+	}
+
+	if index == nil {
+		index = []BundleIndexEntry{}
+	}
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return ExportCatalogBundleResponse{}, err
+	}
+	w, err := zw.Create("index.json")
+	if err != nil {
+		return ExportCatalogBundleResponse{}, err
+	}
+	if _, err := w.Write(indexData); err != nil {
+		return ExportCatalogBundleResponse{}, err
+	}
+	if err := zw.Close(); err != nil {
+		return ExportCatalogBundleResponse{}, err
+	}
+
+	response.Bytes = buf.Len()
+	response.Bundle = base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	return response, nil
+}
+
+// bundleFileName returns a file name (without extension) for an API, derived
+// from its ID. APIs without a usable ID are named by their position, and
+// clashing names get a numeric suffix. The name is recorded in files.
+func bundleFileName(id string, position int, files map[string]bool) string {
+	name := unsafeFileNameChars.ReplaceAllString(id, "_")
+	if name == "" || name == "." || name == ".." {
+		name = fmt.Sprintf("api-%d", position+1)
+	}
+
+	unique := name
+	for i := 2; files[unique]; i++ {
+		unique = fmt.Sprintf("%v-%d", name, i)
+	}
+	files[unique] = true
+
+	return unique
+}
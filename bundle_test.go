@@ -0,0 +1,105 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestExportCatalogBundle(t *testing.T) {
+	pages := map[string]fixture{
+		"1": listPageFixture("/apis", `[{"id":"a","title":"A"},{"id":"b/c","title":"B"}]`, 2, 0, 2, 0),
+		"2": listPageFixture("/apis", `[{"id":"b_c"},{"title":"No ID"}]`, 0, 1, 2, 0),
+	}
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages[r.URL.Query().Get("page")].serve(w)
+	}))
+
+	response, err := exportCatalogBundle(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Entries != 4 || response.PagesScanned != 2 || response.Truncated {
+		t.Errorf("response = %+v, want 4 entries from 2 pages", response)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(response.Bundle)
+	if err != nil {
+		t.Fatalf("decoding bundle: %v", err)
+	}
+	if response.Bytes != len(data) {
+		t.Errorf("bytes = %d, want the bundle size %d", response.Bytes, len(data))
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("opening bundle: %v", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name], err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var index []BundleIndexEntry
+	if err := json.Unmarshal(files["index.json"], &index); err != nil {
+		t.Fatalf("decoding index.json: %v", err)
+	}
+	wantIndex := []BundleIndexEntry{
+		{ID: "a", Name: "A", File: "apis/a.json"},
+		{ID: "b/c", Name: "B", File: "apis/b_c.json"},
+		{ID: "b_c", File: "apis/b_c-2.json"},
+		{Name: "No ID", File: "apis/api-4.json"},
+	}
+	if !reflect.DeepEqual(index, wantIndex) {
+		t.Errorf("index = %+v, want %+v", index, wantIndex)
+	}
+
+	for _, entry := range index {
+		var rec map[string]any
+		if err := json.Unmarshal(files[entry.File], &rec); err != nil {
+			t.Errorf("decoding %v: %v", entry.File, err)
+			continue
+		}
+		if id, _ := rec["id"].(string); id != entry.ID {
+			t.Errorf("%v holds API %q, want %q", entry.File, id, entry.ID)
+		}
+	}
+	if len(files) != len(index)+1 {
+		t.Errorf("bundle holds %d files, want %d", len(files), len(index)+1)
+	}
+}
+
+func TestBundleFileName(t *testing.T) {
+	files := make(map[string]bool)
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"api-1", "api-1"},
+		{"api-1", "api-1-2"},
+		{"api 1", "api_1"},
+		{"", "api-4"},
+		{"..", "api-5"},
+		{".", "api-6"},
+		{"../../etc/passwd", ".._.._etc_passwd"},
+		{"api-4", "api-4-2"},
+	}
+	for i, tt := range tests {
+		if got := bundleFileName(tt.id, i, files); got != tt.want {
+			t.Errorf("bundleFileName(%q, %d) = %q, want %q", tt.id, i, got, tt.want)
+		}
+	}
+}
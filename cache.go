@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Number of catalog pages kept warm by the background refresher.
+const refreshPages = 5
+
+// Cached catalog pages. Caching is enabled by the background refresher; when
+// nil, pages are always fetched from upstream.
+var catalogPages *pageCache
+
+// pageCache is a concurrency-safe cache of fetched list pages, keyed by
+// endpoint and page number.
+type pageCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[pageKey]pageCacheEntry
+}
+
+type pageKey struct {
+	endpoint string
+	page     int
+}
+
+type pageCacheEntry struct {
+	items     []json.RawMessage
+	nextPage  int
+	expiresAt time.Time
+}
+
+// newPageCache returns a page cache whose entries expire after ttl.
+func newPageCache(ttl time.Duration) *pageCache {
+	return &pageCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[pageKey]pageCacheEntry),
+	}
+}
+
+// get returns a cached page, if present and not expired.
+func (c *pageCache) get(endpoint string, page int) (pageCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[pageKey{endpoint, page}]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return pageCacheEntry{}, false
+	}
+	return entry, true
+}
+
+// put stores a fetched page.
+func (c *pageCache) put(endpoint string, page int, items []json.RawMessage, nextPage int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[pageKey{endpoint, page}] = pageCacheEntry{
+		items:     items,
+		nextPage:  nextPage,
+		expiresAt: c.now().Add(c.ttl),
+	}
+}
+
+// fetchPageCached calls fetchPageWithTimeout, serving pages from the catalog
+// cache when caching is enabled.
+func fetchPageCached(ctx context.Context, endpoint string, page int) ([]json.RawMessage, int, error) {
+	if catalogPages == nil {
+		return fetchPageWithTimeout(ctx, endpoint, page)
+	}
+
+	if entry, ok := catalogPages.get(endpoint, page); ok {
+		return entry.items, entry.nextPage, nil
+	}
+
+	items, nextPage, err := fetchPageWithTimeout(ctx, endpoint, page)
+	if err != nil {
+		return nil, 0, err
+	}
+	catalogPages.put(endpoint, page, items, nextPage)
+
+	return items, nextPage, nil
+}
+
+// refresh re-fetches up to n pages of a list endpoint from upstream, replacing
+// the cached pages. It returns the number of pages refreshed.
+func (c *pageCache) refresh(ctx context.Context, endpoint string, n int) (int, error) {
+	ctx = contextWithoutCache(ctx)
+
+	refreshed := 0
+	for page := 1; page != 0 && refreshed < n; {
+		items, nextPage, err := fetchPageWithTimeout(ctx, endpoint, page)
+		if err != nil {
+			return refreshed, err
+		}
+		c.put(endpoint, page, items, nextPage)
+		refreshed++
+
+		if nextPage <= page {
+			break
+		}
+		page = nextPage
+	}
+
+	return refreshed, nil
+}
+
+// runRefresher refreshes the first catalog pages in c immediately and then
+// every interval, until ctx is canceled. Failures are logged; stale entries
+// remain cached until they expire.
+func runRefresher(ctx context.Context, c *pageCache, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		start := time.Now()
+		n, err := c.refresh(ctx, "apis", refreshPages)
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			slog.ErrorContext(ctx, "Catalog refresh failed", "pages", n, "error", err)
+		default:
+			slog.InfoContext(ctx, "Refreshed catalog", "pages", n, "duration", time.Since(start).Round(time.Millisecond))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
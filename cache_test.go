@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock, for use as the now hook of caches.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// waitFor polls cond until it holds, failing the test after a few seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %v", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunRefresher(t *testing.T) {
+	var version, requests atomic.Int64
+	version.Store(1)
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fixture{body: `[{"id":"a","version":` + strconv.FormatInt(version.Load(), 10) + `}]`}.serve(w)
+	}))
+
+	const (
+		interval = 10 * time.Millisecond
+		ttl      = time.Hour
+	)
+	clock := newFakeClock()
+	c := newPageCache(ttl)
+	c.now = clock.Now
+
+	cachedVersion := func() string {
+		entry, ok := c.get("apis", 1)
+		if !ok {
+			return ""
+		}
+		return string(entry.items[0])
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runRefresher(ctx, c, interval)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	// The refresher fills the cache immediately.
+	waitFor(t, "the initial refresh", func() bool { return cachedVersion() != "" })
+	if got := cachedVersion(); got != `{"id":"a","version":1}` {
+		t.Fatalf("cached page = %v, want version 1", got)
+	}
+
+	// Without the refresher, the page would expire after the TTL. Refreshes
+	// store the new upstream version, with a renewed expiry.
+	version.Store(2)
+	clock.Advance(ttl - time.Second)
+	waitFor(t, "a refresh with version 2", func() bool { return cachedVersion() == `{"id":"a","version":2}` })
+
+	clock.Advance(2 * time.Second)
+	if _, ok := c.get("apis", 1); !ok {
+		t.Error("refreshed page expired at the expiry of the initial refresh")
+	}
+
+	// Canceling the context stops the refresher.
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresher didn't stop after cancellation")
+	}
+	// A request in flight at cancellation may still reach the server, so
+	// let it settle before counting.
+	time.Sleep(5 * interval)
+	n := requests.Load()
+	time.Sleep(5 * interval)
+	if requests.Load() != n {
+		t.Error("refresher kept fetching after cancellation")
+	}
+}
+
+func TestPageCacheExpiry(t *testing.T) {
+	clock := newFakeClock()
+	c := newPageCache(time.Minute)
+	c.now = clock.Now
+
+	c.put("apis", 1, nil, 2)
+	if entry, ok := c.get("apis", 1); !ok || entry.nextPage != 2 {
+		t.Fatalf("got %+v, %v, want the stored page", entry, ok)
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := c.get("apis", 1); ok {
+		t.Error("expired page returned")
+	}
+}
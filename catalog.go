@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default number of pages tools traversing the catalog may fetch per call.
+const defaultMaxPages = 50
+
+// Default timeout for fetching a single page during a traversal.
+const defaultPageTimeout = 10 * time.Second
+
+// errPageTimeout is returned (wrapped) by walkPages when fetching a single page
+// exceeded the per-page timeout.
+var errPageTimeout = errors.New("page fetch timed out")
+
+// errStopWalk can be returned by a walkPages callback to stop the traversal
+// without an error.
+var errStopWalk = errors.New("stop walk")
+
+// listPage represents a page fetched from a paginated list endpoint.
+type listPage struct {
+	// Body is the (undecoded) JSON response body.
+	Body json.RawMessage
+	// NextPage, PrevPage and LastPage are the page numbers of the
+	// corresponding Link relations, or 0 if there is none.
+	NextPage int
+	PrevPage int
+	LastPage int
+	// TotalCount is the total number of items, or 0 if unknown.
+	TotalCount int
+}
+
+// fetchList fetches page number page of a paginated list endpoint from apiURL.
+// Link relations are only taken into account when they're consistent with the
+// current page: e.g. a "next" relation must point beyond it, so that NextPage
+// is reliably 0 on the last page.
+func fetchList(ctx context.Context, client *http.Client, apiURL string, page int) (listPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return listPage{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := doWithRetry(ctx, client, req)
+	if err != nil {
+		return listPage{}, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkStatus(resp); err != nil {
+		return listPage{}, err
+	}
+
+	body, err := decodeJSONBody(resp, emptyList)
+	if err != nil {
+		return listPage{}, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	lp := listPage{
+		Body:       body,
+		TotalCount: totalCountFromHeader(resp.Header),
+	}
+	if nextPage := nextPageFromHeader(resp.Header); nextPage > page {
+		lp.NextPage = nextPage
+	}
+	if prevPage := pageFromHeader(resp.Header, "prev"); prevPage > 0 && prevPage < page {
+		lp.PrevPage = prevPage
+	}
+	if lastPage := pageFromHeader(resp.Header, "last"); lastPage >= page {
+		lp.LastPage = lastPage
+	}
+
+	return lp, nil
+}
+
+// fetchPage fetches a single page of a paginated list endpoint (e.g. `apis`)
+// and returns its items, along with the next page number (or 0 if there is no
+// next page).
+func fetchPage(ctx context.Context, endpoint string, page int) ([]json.RawMessage, int, error) {
+	apiURL, err := buildURL(apiBaseURL, pageQuery(page), endpoint)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error building URL: %w", err)
+	}
+
+	lp, err := fetchList(ctx, httpClient, apiURL, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items, err := decodeItems(lp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return items, lp.NextPage, nil
+}
+
+// walkPages fetches the pages of a list endpoint, starting at page 1, and calls
+// fn for every item. At most maxPages pages are fetched. It returns the number
+// of pages fetched and whether more pages were available when it stopped.
+//
+// Items are deduplicated by ID: pages may overlap when the catalog changes
+// during the traversal, so an item seen before is skipped (and logged).
+//
+// With -link-fallback, a full page without a "next" relation (the upstream
+// occasionally omits the Link header) is followed by the next page number
+// anyway. The size of the first page is taken as the page size. A guessed page
+// that is empty or not found ends the traversal.
+func walkPages(ctx context.Context, endpoint string, maxPages int, fn func(item json.RawMessage) error) (int, bool, error) {
+	seen := make(map[string]bool)
+	duplicates := 0
+	defer func() {
+		if duplicates == 0 {
+			return
+		}
+		attrs := []any{"endpoint", endpoint, "duplicates", duplicates}
+		if tool := toolFromContext(ctx); tool != "" {
+			attrs = append(attrs, "tool", tool)
+		}
+		slog.WarnContext(ctx, "Skipped duplicate items while traversing catalog", attrs...)
+	}()
+
+	pages, pageSize, guessed := 0, 0, false
+	for page := 1; page != 0; {
+		if pages >= maxPages {
+			return pages, true, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return pages, false, err
+		}
+
+		items, nextPage, err := fetchPageCached(ctx, endpoint, page)
+		var statusErr statusError
+		if guessed && errors.As(err, &statusErr) && statusErr.code == http.StatusNotFound {
+			break
+		}
+		if err != nil {
+			return pages, false, fmt.Errorf("page %d: %w", page, err)
+		}
+		pages++
+
+		if page == 1 {
+			pageSize = len(items)
+		}
+		guessed = linkFallback && nextPage == 0 && len(items) > 0 && len(items) >= pageSize
+		if guessed {
+			nextPage = page + 1
+		}
+
+		for _, item := range items {
+			if id := itemID(item); id != "" {
+				if seen[id] {
+					duplicates++
+					continue
+				}
+				seen[id] = true
+			}
+			if err := fn(item); errors.Is(err, errStopWalk) {
+				return pages, nextPage != 0, nil
+			} else if err != nil {
+				return pages, false, err
+			}
+		}
+
+		// Guard against a stale or looping "next" relation.
+		if nextPage <= page {
+			break
+		}
+		page = nextPage
+	}
+
+	return pages, false, nil
+}
+
+// fetchPageWithTimeout calls fetchPage, bounded by the per-page timeout (if
+// configured). When the per-page timeout fires while ctx itself is still alive,
+// errPageTimeout is returned.
+func fetchPageWithTimeout(ctx context.Context, endpoint string, page int) ([]json.RawMessage, int, error) {
+	if pageTimeout <= 0 {
+		return fetchPage(ctx, endpoint, page)
+	}
+
+	pageCtx, cancel := context.WithTimeout(ctx, pageTimeout)
+	defer cancel()
+
+	items, nextPage, err := fetchPage(pageCtx, endpoint, page)
+	if err != nil && ctx.Err() == nil && errors.Is(pageCtx.Err(), context.DeadlineExceeded) {
+		return nil, 0, fmt.Errorf("%w after %v", errPageTimeout, pageTimeout)
+	}
+
+	return items, nextPage, err
+}
+
+// nextPageFromHeader returns the page number of the "next" relation in the
+// Link header, or 0 if there is none.
+func nextPageFromHeader(header http.Header) int {
+	return pageFromHeader(header, "next")
+}
+
+// pageFromHeader returns the page number of the link with relation type rel
+// (e.g. "next", "prev" or "last") in the Link header, or 0 if there is none.
+func pageFromHeader(header http.Header, rel string) int {
+	linkHeader := header.Get("Link")
+	if linkHeader == "" {
+		return 0
+	}
+
+	for _, link := range parseLinkHeader(linkHeader) {
+		if link.Rel != rel {
+			continue
+		}
+		parsedURL, err := url.Parse(link.URL)
+		if err != nil {
+			return 0
+		}
+		page, err := strconv.Atoi(parsedURL.Query().Get("page"))
+		if err != nil {
+			return 0
+		}
+		return page
+	}
+
+	return 0
+}
+
+// Response header holding the total number of items of a list endpoint.
+const totalCountHeader = "X-Total-Count"
+
+// totalCountFromHeader returns the total number of items reported by the
+// X-Total-Count header, or 0 if it's missing or invalid.
+func totalCountFromHeader(header http.Header) int {
+	n, err := strconv.Atoi(strings.TrimSpace(header.Get(totalCountHeader)))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// itemID returns the raw JSON value of the `id` field of a list item, or an
+// empty string if it has none.
+func itemID(item json.RawMessage) string {
+	var v struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(item, &v); err != nil || len(v.ID) == 0 || string(v.ID) == "null" {
+		return ""
+	}
+	return string(v.ID)
+}
+
+// decodeItems returns the items of a list response body. Besides a bare JSON
+// array, an object wrapping the array in a `results` or `data` field is
+// accepted.
+func decodeItems(body json.RawMessage) ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err == nil {
+		return items, nil
+	}
+
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		return nil, errors.New("expected a JSON array or object")
+	}
+	for _, key := range []string{"results", "data"} {
+		if raw, ok := wrapper[key]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return nil, fmt.Errorf("field %q: %w", key, err)
+			}
+			return items, nil
+		}
+	}
+
+	return nil, errors.New("no list of items found in response")
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mockCatalog is a paginated list endpoint served by a test server, with
+// perPage items on each of its pages. Items are {"id": "<page>-<index>"}.
+type mockCatalog struct {
+	pages   int
+	perPage int
+	// staleNext adds a "next" relation pointing at the last page itself to
+	// the last page, as a stale Link header would.
+	staleNext bool
+	// slowPage is a page that isn't served until the request is canceled.
+	slowPage int
+
+	requests atomic.Int64
+}
+
+// newMockCatalog starts a test server (see newTestServer) serving a catalog of
+// the given number of pages at /apis and /repositories.
+func newMockCatalog(t testing.TB, pages, perPage int) *mockCatalog {
+	c := &mockCatalog{pages: pages, perPage: perPage}
+	newTestServer(t, c)
+	return c
+}
+
+func (c *mockCatalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.requests.Add(1)
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 || page > c.pages {
+		fixture{status: http.StatusNotFound, body: `{"message":"page not found"}`}.serve(w)
+		return
+	}
+
+	if page == c.slowPage {
+		<-r.Context().Done()
+		return
+	}
+
+	items := make([]string, c.perPage)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"id":"%d-%d"}`, page, i)
+	}
+
+	link := func(page int, rel string) string {
+		return fmt.Sprintf(`<%v?page=%d>; rel="%v"`, r.URL.Path, page, rel)
+	}
+	links := []string{link(1, "first"), link(c.pages, "last")}
+	if page > 1 {
+		links = append(links, link(page-1, "prev"))
+	}
+	if page < c.pages {
+		links = append(links, link(page+1, "next"))
+	} else if c.staleNext {
+		links = append(links, link(page, "next"))
+	}
+
+	fixture{
+		header: http.Header{
+			"Link":          {strings.Join(links, ", ")},
+			"X-Total-Count": {strconv.Itoa(c.pages * c.perPage)},
+		},
+		body: "[" + strings.Join(items, ",") + "]",
+	}.serve(w)
+}
+
+func TestFetchList(t *testing.T) {
+	t.Run("pagination", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			fixture fixture
+			page    int
+			want    listPage
+		}{
+			{
+				name:    "middle page",
+				fixture: listPageFixture("/apis", `[{"id":"a"}]`, 3, 1, 5, 42),
+				page:    2,
+				want:    listPage{NextPage: 3, PrevPage: 1, LastPage: 5, TotalCount: 42},
+			},
+			{
+				name:    "last page",
+				fixture: listPageFixture("/apis", `[{"id":"a"}]`, 0, 4, 5, 0),
+				page:    5,
+				want:    listPage{PrevPage: 4, LastPage: 5},
+			},
+			{
+				name:    "inconsistent relations",
+				fixture: listPageFixture("/apis", `[{"id":"a"}]`, 2, 2, 1, 0),
+				page:    2,
+				want:    listPage{},
+			},
+			{
+				name:    "no Link header",
+				fixture: fixture{body: `[{"id":"a"}]`},
+				page:    1,
+				want:    listPage{},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				newFixtureServer(t, map[string]fixture{"/apis": tt.fixture})
+
+				lp, err := fetchList(t.Context(), httpClient, apiBaseURL+"/apis", tt.page)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(lp.Body) != `[{"id":"a"}]` {
+					t.Errorf("body = %s, want the fixture body", lp.Body)
+				}
+				lp.Body = nil
+				if !reflect.DeepEqual(lp, tt.want) {
+					t.Errorf("fetchList() = %+v, want %+v", lp, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusNoContent},
+		})
+
+		lp, err := fetchList(t.Context(), httpClient, apiBaseURL+"/apis", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(lp.Body) != "[]" {
+			t.Errorf("body = %s, want []", lp.Body)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			fixture  fixture
+			category string
+			want     string
+		}{
+			{"upstream error", fixture{status: http.StatusInternalServerError, body: `{"message":"boom"}`}, "upstream", "boom"},
+			{"not found", fixture{status: http.StatusNotFound, body: `{"message":"page not found"}`}, "upstream", "page not found"},
+			{"malformed body", fixture{body: `[{"id":`}, "decode", "error parsing response"},
+			{"HTML error page", fixture{header: http.Header{"Content-Type": {"text/html"}}, body: `<html></html>`}, "upstream", "HTML page"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				newFixtureServer(t, map[string]fixture{"/apis": tt.fixture})
+
+				_, err := fetchList(t.Context(), httpClient, apiBaseURL+"/apis", 1)
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("error %q doesn't contain %q", err, tt.want)
+				}
+				if got := errorCategory(err); got != tt.category {
+					t.Errorf("category = %q, want %q", got, tt.category)
+				}
+			})
+		}
+	})
+}
+
+func TestLastPageHasNoNextPage(t *testing.T) {
+	for _, staleNext := range []bool{false, true} {
+		t.Run(fmt.Sprintf("staleNext=%v", staleNext), func(t *testing.T) {
+			c := newMockCatalog(t, 3, 2)
+			c.staleNext = staleNext
+
+			t.Run("list_apis", func(t *testing.T) {
+				res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":3}`)
+
+				var got map[string]json.RawMessage
+				decodeResult(t, res, &got)
+				for key := range got {
+					if strings.Contains(key, "next") {
+						t.Errorf("result of the last page has %q: %s", key, got[key])
+					}
+				}
+				if string(got["last_page"]) != "3" {
+					t.Errorf("last_page = %s, want 3", got["last_page"])
+				}
+			})
+
+			t.Run("fetchPage", func(t *testing.T) {
+				items, nextPage, err := fetchPage(t.Context(), "apis", 3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(items) != 2 || nextPage != 0 {
+					t.Errorf("got %d items and next page %d, want 2 items and next page 0", len(items), nextPage)
+				}
+			})
+
+			t.Run("fetchPageCached", func(t *testing.T) {
+				old := catalogPages
+				t.Cleanup(func() { catalogPages = old })
+				catalogPages = newPageCache(time.Minute)
+
+				// The second call is served from the cache.
+				for range 2 {
+					_, nextPage, err := fetchPageCached(t.Context(), "apis", 3)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if nextPage != 0 {
+						t.Errorf("next page = %d, want 0", nextPage)
+					}
+				}
+
+				// Pages cached by the refresher, too.
+				n, err := catalogPages.refresh(t.Context(), "apis", refreshPages)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n != 3 {
+					t.Errorf("refreshed %d pages, want 3", n)
+				}
+				requests := c.requests.Load()
+				if _, nextPage, _ := fetchPageCached(t.Context(), "apis", 3); nextPage != 0 {
+					t.Errorf("next page of refreshed last page = %d, want 0", nextPage)
+				}
+				if c.requests.Load() != requests {
+					t.Error("refreshed last page wasn't served from the cache")
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkFetchPage(b *testing.B) {
+	newMockCatalog(b, 1, 100)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		items, _, err := fetchPage(b.Context(), "apis", 1)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(items) != 100 {
+			b.Fatalf("got %d items, want 100", len(items))
+		}
+	}
+}
+
+func BenchmarkWalkPages(b *testing.B) {
+	newMockCatalog(b, 10, 100)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		n := 0
+		_, _, err := walkPages(b.Context(), "apis", defaultMaxPages, func(json.RawMessage) error {
+			n++
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if n != 1000 {
+			b.Fatalf("walked %d items, want 1000", n)
+		}
+	}
+}
+
+func TestFetchPageWithTimeout(t *testing.T) {
+	c := newMockCatalog(t, 3, 2)
+	c.slowPage = 2
+	setPageTimeout(t, 50*time.Millisecond)
+
+	if _, _, err := fetchPageWithTimeout(context.Background(), "apis", 1); err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+
+	_, _, err := fetchPageWithTimeout(context.Background(), "apis", 2)
+	if !errors.Is(err, errPageTimeout) {
+		t.Fatalf("page 2: got error %v, want errPageTimeout", err)
+	}
+
+	t.Run("canceled parent", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, _, err := fetchPageWithTimeout(ctx, "apis", 2)
+		if err == nil || errors.Is(err, errPageTimeout) {
+			t.Errorf("got error %v, want the parent's error rather than errPageTimeout", err)
+		}
+	})
+
+	t.Run("partial results", func(t *testing.T) {
+		res := callTool(t, createListAllAPIsTool(), `{}`)
+
+		var got ListAllAPIsResponse
+		decodeResult(t, res, &got)
+		if len(got.APIs) != 2 || got.PageCount != 1 {
+			t.Errorf("got %d APIs from %d pages, want the 2 APIs of page 1", len(got.APIs), got.PageCount)
+		}
+		if !got.Truncated {
+			t.Error("truncated = false after a page timeout")
+		}
+		if !strings.Contains(got.Error, "page 2") || !strings.Contains(got.Error, errPageTimeout.Error()) {
+			t.Errorf("error = %q, want the timeout of page 2", got.Error)
+		}
+	})
+}
+
+// setPageTimeout sets the per-page timeout for the duration of the test.
+func setPageTimeout(t *testing.T, d time.Duration) {
+	t.Helper()
+
+	old := pageTimeout
+	t.Cleanup(func() { pageTimeout = old })
+	pageTimeout = d
+}
+
+func TestWalkPagesSkipsDuplicates(t *testing.T) {
+	pages := map[string]fixture{
+		"1": listPageFixture("/apis", `[{"id":"a"},{"id":"b"}]`, 2, 0, 2, 0),
+		"2": listPageFixture("/apis", `[{"id":"b"},{"id":"c"}]`, 0, 1, 2, 0),
+	}
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages[r.URL.Query().Get("page")].serve(w)
+	}))
+
+	var ids []string
+	n, more, err := walkPages(context.Background(), "apis", maxPages, func(item json.RawMessage) error {
+		ids = append(ids, itemID(item))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 || more {
+		t.Errorf("walked %d pages (more: %v), want 2 pages", n, more)
+	}
+	if want := []string{`"a"`, `"b"`, `"c"`}; !slices.Equal(ids, want) {
+		t.Errorf("items = %v, want %v with the repeated b counted once", ids, want)
+	}
+}
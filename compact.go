@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Short keys used for record fields when -compact-records is set. Fields not
+// listed keep their name. Short keys must not be upstream field names
+// themselves, e.g. "type" is one, so "api_type" is shortened to "at".
+var compactKeys = map[string]string{
+	"service_name":       "sn",
+	"description":        "desc",
+	"organization":       "org",
+	"api_type":           "at",
+	"api_authentication": "auth",
+	"environments":       "envs",
+	"api_url":            "api",
+	"specification_url":  "spec",
+	"documentation_url":  "docs",
+	"contact":            "ct",
+	"terms_of_use":       "tou",
+}
+
+// GetCompactKeysParams represents the parameters for the getCompactKeys tool.
+type GetCompactKeysParams struct{}
+
+// GetCompactKeysResponse represents the response from the getCompactKeys tool.
+type GetCompactKeysResponse struct {
+	Enabled bool              `json:"enabled"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// createGetCompactKeysTool creates a tool for getting the mapping of record
+// field names to the short keys used in compact mode.
+func createGetCompactKeysTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetCompactKeysParams]{
+		Name: "get_compact_keys",
+		Description: "Get the mapping of upstream record field names to the short keys used when the server runs " +
+			"in compact mode, in which null and empty fields are also omitted. `enabled` reports whether " +
+			"compact mode is on.",
+		HandleFunc: func(ctx context.Context, params GetCompactKeysParams) *mcp.CallToolResult {
+			result, err := marshalResult(GetCompactKeysResponse{
+				Enabled: compactRecords,
+				Keys:    compactKeys,
+			}, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// compactValue omits null and empty fields from all objects in a decoded JSON
+// value, and renames their keys according to compactKeys. A key isn't renamed
+// if the object already has a field with the short key, so that no field is
+// overwritten.
+func compactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		compacted := make(map[string]any, len(v))
+		for k, elem := range v {
+			elem = compactValue(elem)
+			if isEmptyValue(elem) {
+				continue
+			}
+			if short, ok := compactKeys[k]; ok {
+				if _, exists := v[short]; !exists {
+					k = short
+				}
+			}
+			compacted[k] = elem
+		}
+		return compacted
+	case []any:
+		for i, elem := range v {
+			v[i] = compactValue(elem)
+		}
+	}
+	return v
+}
+
+// isEmptyValue reports whether a decoded JSON value is null, an empty string,
+// an empty array or an empty object.
+func isEmptyValue(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
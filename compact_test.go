@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompactValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "renames keys",
+			in:   `{"id":"a","service_name":"Test","api_type":"rest_json","organization":{"name":"Org"}}`,
+			want: `{"at":"rest_json","id":"a","org":{"name":"Org"},"sn":"Test"}`,
+		},
+		{
+			name: "omits empties",
+			in:   `{"id":"a","description":"","contact":{"email":null,"url":""},"environments":[],"terms_of_use":{},"api_url":null}`,
+			want: `{"id":"a"}`,
+		},
+		{
+			name: "omits empties in nested records",
+			in:   `[{"id":"a","environments":[{"name":"production","api_url":"https://example.com","specification_url":""}]}]`,
+			want: `[{"envs":[{"api":"https://example.com","name":"production"}],"id":"a"}]`,
+		},
+		{
+			name: "keeps type next to api_type",
+			in:   `{"type":"rest","api_type":"rest_json"}`,
+			want: `{"at":"rest_json","type":"rest"}`,
+		},
+		{
+			name: "doesn't overwrite an existing short key",
+			in:   `{"api":"v2","api_url":"https://example.com"}`,
+			want: `{"api":"v2","api_url":"https://example.com"}`,
+		},
+		{
+			name: "keeps zero values",
+			in:   `{"id":0,"deprecated":false}`,
+			want: `{"deprecated":false,"id":0}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			if err := json.Unmarshal([]byte(tt.in), &v); err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(compactValue(v))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactKeysDontCollide(t *testing.T) {
+	seen := make(map[string]string)
+	for long, short := range compactKeys {
+		if _, ok := compactKeys[short]; ok {
+			t.Errorf("short key %q of %q is itself a mapped field name", short, long)
+		}
+		if other, ok := seen[short]; ok {
+			t.Errorf("fields %q and %q share the short key %q", long, other, short)
+		}
+		seen[short] = long
+	}
+	for _, field := range []string{"id", "type", "name", "title", "url"} {
+		if long, ok := seen[field]; ok {
+			t.Errorf("short key of %q collides with upstream field %q", long, field)
+		}
+	}
+}
+
+func TestCompactRecordsOutput(t *testing.T) {
+	old := compactRecords
+	t.Cleanup(func() { compactRecords = old })
+	compactRecords = true
+
+	newFixtureServer(t, map[string]fixture{
+		"/apis/a": {body: `{"id":"a","type":"rest","api_type":"rest_json","description":null,"contact":{}}`},
+	})
+
+	var got map[string]any
+	decodeResult(t, callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`), &got)
+	want := map[string]any{"id": "a", "type": "rest", "at": "rest_json"}
+	if len(got) != len(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%v = %v, want %v", k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decompressTransport is an http.RoundTripper that decodes gzip and deflate
+// compressed response bodies. The standard transport only does so when it
+// added the `Accept-Encoding` header itself; this also covers responses that
+// are compressed regardless, or requests that set the header explicitly.
+// Decompressed bodies are still subject to -max-response-bytes.
+type decompressTransport struct {
+	next http.RoundTripper
+}
+
+func (t decompressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.Uncompressed {
+		return resp, err
+	}
+
+	var body io.ReadCloser
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		body = &lazyReader{src: resp.Body, open: func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }}
+	case "deflate":
+		body = &lazyReader{src: resp.Body, open: zlib.NewReader}
+	default:
+		// Identity, or an encoding that can't be decoded here, which makes
+		// decoding the body fail with a descriptive error.
+		return resp, nil
+	}
+
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return resp, nil
+}
+
+// lazyReader decompresses src, creating the decompressor on the first read, so
+// that an empty body (e.g. of a HEAD request) isn't an error.
+type lazyReader struct {
+	src  io.ReadCloser
+	open func(io.Reader) (io.ReadCloser, error)
+	r    io.ReadCloser
+	err  error
+}
+
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.r == nil && l.err == nil {
+		l.r, l.err = l.open(l.src)
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.r.Read(p)
+}
+
+func (l *lazyReader) Close() error {
+	return l.src.Close()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecompressTransport(t *testing.T) {
+	const body = `{"id":"a","title":"Compressed API"}`
+
+	compress := func(t *testing.T, newWriter func(io.Writer) io.WriteCloser) []byte {
+		t.Helper()
+
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	gzipped := compress(t, func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })
+	deflated := compress(t, func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })
+
+	tests := []struct {
+		name         string
+		method       string
+		encoding     string
+		data         []byte
+		wantBody     string
+		wantEncoding string
+	}{
+		{"gzip", http.MethodGet, "gzip", gzipped, body, ""},
+		{"x-gzip", http.MethodGet, "X-Gzip", gzipped, body, ""},
+		{"deflate", http.MethodGet, "deflate", deflated, body, ""},
+		{"identity", http.MethodGet, "", []byte(body), body, ""},
+		{"unsupported encoding", http.MethodGet, "br", []byte("\x1b\x00"), "\x1b\x00", "br"},
+		{"empty HEAD response", http.MethodHead, "gzip", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Write(tt.data)
+			}))
+			t.Cleanup(srv.Close)
+
+			req, err := http.NewRequest(tt.method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// An explicit Accept-Encoding keeps the standard transport from
+			// decoding the response itself.
+			req.Header.Set("Accept-Encoding", "gzip, deflate")
+
+			resp, err := decompressTransport{next: http.DefaultTransport}.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if string(got) != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+			if enc := resp.Header.Get("Content-Encoding"); enc != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", enc, tt.wantEncoding)
+			}
+		})
+	}
+}
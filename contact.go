@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Timeout for checking whether a contact URL resolves.
+const contactURLTimeout = 5 * time.Second
+
+// ValidateAPIContactParams represents the parameters for the validateAPIContact tool.
+// The `id` parameter is required.
+type ValidateAPIContactParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// ContactValidationReport represents the response from the validateAPIContact tool.
+type ContactValidationReport struct {
+	ID    string          `json:"id"`
+	Email FieldValidation `json:"email"`
+	URL   FieldValidation `json:"url"`
+}
+
+// FieldValidation represents the validation result of a single contact field.
+type FieldValidation struct {
+	Present bool   `json:"present"`
+	Value   string `json:"value,omitempty"`
+	Valid   bool   `json:"valid"`
+	Status  int    `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// createValidateAPIContactTool creates a tool for validating the contact
+// information of an API.
+func createValidateAPIContactTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ValidateAPIContactParams]{
+		Name: "validate_api_contact",
+		Description: "Validate the contact information of an API by ID: checks that the contact email is " +
+			"syntactically valid and that the contact URL resolves. Returns a per-field validity report.",
+		HandleFunc: func(ctx context.Context, params ValidateAPIContactParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			contact, _ := api["contact"].(map[string]any)
+
+			report := ContactValidationReport{
+				ID:    params.ID,
+				Email: validateEmail(recordString(contact, "email")),
+				URL:   validateContactURL(ctx, recordString(contact, "url")),
+			}
+
+			result, err := marshalResult(report, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// validateEmail checks that an email address is a bare, syntactically valid
+// address (without display name).
+func validateEmail(email string) FieldValidation {
+	v := FieldValidation{Present: email != "", Value: email}
+	if !v.Present {
+		return v
+	}
+
+	addr, err := mail.ParseAddress(email)
+	switch {
+	case err != nil:
+		v.Error = err.Error()
+	case addr.Address != email:
+		v.Error = "email address must not contain a display name"
+	case !strings.Contains(addr.Address[strings.LastIndex(addr.Address, "@"):], "."):
+		v.Error = "email domain is not fully qualified"
+	default:
+		v.Valid = true
+	}
+
+	return v
+}
+
+// validateContactURL checks that a contact URL is an absolute HTTP(S) URL on a
+// public host, and that it resolves to a non-error response.
+func validateContactURL(ctx context.Context, rawURL string) FieldValidation {
+	v := FieldValidation{Present: rawURL != "", Value: rawURL}
+	if !v.Present {
+		return v
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, contactURLTimeout)
+	defer cancel()
+
+	status, err := probeURL(ctx, rawURL)
+	if err != nil {
+		v.Error = err.Error()
+		return v
+	}
+
+	v.Status = status
+	if status >= 400 {
+		v.Error = fmt.Sprintf("URL responded with status %d", status)
+		return v
+	}
+	v.Valid = true
+
+	return v
+}
+
+// probeURL issues a HEAD request (falling back to GET if HEAD isn't allowed)
+// and returns the response status code. The URL must be public, see
+// newPublicURLRequest.
+func probeURL(ctx context.Context, rawURL string) (int, error) {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := newPublicURLRequest(ctx, method, rawURL)
+		if err != nil {
+			return 0, err
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+
+		status = resp.StatusCode
+		if status != http.StatusMethodNotAllowed {
+			break
+		}
+	}
+
+	return status, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Maximum size of a DCAT description that will be read into memory.
+const maxDCATBytes = 2 << 20
+
+// Fields of an API record that may link to a DCAT (JSON-LD) description.
+var dcatURLFields = []string{"dcat_url", "dcat_ap_url", "jsonld_url", "metadata_url"}
+
+// GetAPIDCATParams represents the parameters for the getAPIDCAT tool.
+// The `id` parameter is required.
+type GetAPIDCATParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// GetAPIDCATResponse represents the response from the getAPIDCAT tool.
+type GetAPIDCATResponse struct {
+	ID      string          `json:"id"`
+	HasDCAT bool            `json:"has_dcat"`
+	URL     string          `json:"url,omitempty"`
+	DCAT    json.RawMessage `json:"dcat,omitempty"`
+	Note    string          `json:"note,omitempty"`
+}
+
+// createGetAPIDCATTool creates a tool for getting the DCAT description of an
+// API.
+func createGetAPIDCATTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPIDCATParams]{
+		Name: "get_api_dcat",
+		Description: "Get the DCAT-AP (JSON-LD) metadata description of an API by ID, if the register links to one. " +
+			"APIs without a published description return `has_dcat: false`.",
+		HandleFunc: func(ctx context.Context, params GetAPIDCATParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			response := GetAPIDCATResponse{
+				ID:  params.ID,
+				URL: recordString(api, dcatURLFields...),
+			}
+
+			if response.URL == "" {
+				response.Note = "The API record doesn't link to a DCAT description."
+			} else {
+				response.DCAT, err = fetchDCAT(ctx, response.URL)
+				if err != nil {
+					return newToolCallErrorResult("Error fetching DCAT description: %v", err)
+				}
+				response.HasDCAT = true
+			}
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// fetchDCAT fetches a DCAT description, preferring its JSON-LD representation.
+// The URL must be public, see newPublicURLRequest.
+func fetchDCAT(ctx context.Context, dcatURL string) (json.RawMessage, error) {
+	req, err := newPublicURLRequest(ctx, http.MethodGet, dcatURL)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json;q=0.9")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return nil, fmt.Errorf("DCAT server returned %v", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDCATBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxDCATBytes {
+		return nil, fmt.Errorf("DCAT description exceeds %d bytes", maxDCATBytes)
+	}
+	if !json.Valid(body) {
+		return nil, errors.New("DCAT description isn't valid JSON-LD")
+	}
+
+	return body, nil
+}
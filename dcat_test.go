@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+// newDCATServer starts a test server serving an API record linking to a DCAT
+// description at /dcat, which redirects to redirect if set.
+func newDCATServer(t *testing.T, redirect string) {
+	t.Helper()
+
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/a":
+			fixture{body: `{"id":"a","dcat_url":"` + srvURL + `/dcat"}`}.serve(w)
+		case "/dcat":
+			if redirect != "" {
+				http.Redirect(w, r, redirect, http.StatusFound)
+				return
+			}
+			fixture{header: http.Header{"Content-Type": {"application/ld+json"}}, body: `{"@type":"dcat:DataService"}`}.serve(w)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	srvURL = srv.URL
+}
+
+func TestGetAPIDCAT(t *testing.T) {
+	allowPrivateIPs(t)
+	newDCATServer(t, "")
+
+	var got GetAPIDCATResponse
+	decodeResult(t, callTool(t, createGetAPIDCATTool(), `{"id":"a"}`), &got)
+	if !got.HasDCAT || string(got.DCAT) != `{"@type":"dcat:DataService"}` {
+		t.Errorf("got %+v, want the DCAT description", got)
+	}
+}
+
+func TestGetAPIDCATRejectsNonPublicURL(t *testing.T) {
+	t.Run("private address", func(t *testing.T) {
+		newDCATServer(t, "")
+
+		res := callTool(t, createGetAPIDCATTool(), `{"id":"a"}`)
+		expectError(t, res, "is not allowed")
+	})
+
+	t.Run("redirect to private address", func(t *testing.T) {
+		allowPrivateIPs(t)
+		newDCATServer(t, "http://169.254.169.254/latest/meta-data/")
+
+		res := callTool(t, createGetAPIDCATTool(), `{"id":"a"}`)
+		expectError(t, res, `host "169.254.169.254" is not allowed`)
+	})
+
+	t.Run("host not allowed", func(t *testing.T) {
+		allowPrivateIPs(t)
+		setAllowedHosts(t, "overheid.nl")
+		newDCATServer(t, "")
+
+		res := callTool(t, createGetAPIDCATTool(), `{"id":"a"}`)
+		expectError(t, res, "not in the allowed hosts")
+	})
+}
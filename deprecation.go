@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeprecationInfo represents the deprecation signals of an API, as added to
+// the result of the getAPI tool in a `_deprecation` field.
+type DeprecationInfo struct {
+	Deprecated bool     `json:"deprecated"`
+	Sources    []string `json:"sources"`
+	Since      string   `json:"since,omitempty"`
+	Sunset     string   `json:"sunset,omitempty"`
+}
+
+// detectDeprecation returns the deprecation info of an API record and the
+// headers of its response, or nil if there are no deprecation signals. The
+// signals are a (truthy) `deprecated` field in the record, and the
+// `Deprecation` (RFC 9745) and `Sunset` (RFC 8594) response headers. Dates are
+// formatted as RFC 3339 when they can be parsed, and returned as-is otherwise.
+func detectDeprecation(api json.RawMessage, header http.Header) *DeprecationInfo {
+	info := &DeprecationInfo{}
+
+	var rec map[string]any
+	if err := json.Unmarshal(api, &rec); err == nil {
+		switch v := rec["deprecated"].(type) {
+		case bool:
+			info.Deprecated = v
+		case string:
+			if v = strings.TrimSpace(v); v != "" && !strings.EqualFold(v, "false") {
+				info.Deprecated = true
+				if !strings.EqualFold(v, "true") {
+					info.Since = formatDeprecationDate(v)
+				}
+			}
+		}
+		if info.Deprecated {
+			info.Sources = append(info.Sources, "field")
+		}
+		info.Sunset = formatDeprecationDate(recordString(rec, "sunset", "sunset_date"))
+	}
+
+	if v := strings.TrimSpace(header.Get("Deprecation")); v != "" && !strings.EqualFold(v, "false") {
+		info.Deprecated = true
+		info.Sources = append(info.Sources, "deprecation_header")
+		if info.Since == "" && !strings.EqualFold(v, "true") {
+			info.Since = formatDeprecationDate(v)
+		}
+	}
+	if v := strings.TrimSpace(header.Get("Sunset")); v != "" {
+		info.Deprecated = true
+		info.Sources = append(info.Sources, "sunset_header")
+		if info.Sunset == "" {
+			info.Sunset = formatDeprecationDate(v)
+		}
+	}
+
+	if !info.Deprecated {
+		return nil
+	}
+	slices.Sort(info.Sources)
+
+	return info
+}
+
+// formatDeprecationDate formats a date of a deprecation signal as RFC 3339:
+// an RFC 9745 structured field date (`@` followed by a Unix timestamp), an
+// HTTP date, or an RFC 3339 date (with or without time). Other values are
+// returned as-is.
+func formatDeprecationDate(s string) string {
+	if s == "" {
+		return ""
+	}
+	if ts, ok := strings.CutPrefix(s, "@"); ok {
+		if sec, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			return time.Unix(sec, 0).UTC().Format(time.RFC3339)
+		}
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return s
+}
+
+// deprecationWarning returns the warning shown before a deprecated API.
+func deprecationWarning(id string, info *DeprecationInfo) string {
+	warning := "Warning: API " + id + " is deprecated"
+	if info.Sunset != "" {
+		warning += " and will be sunset at " + info.Sunset
+	}
+	return warning + ". Consider using an alternative API."
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+func TestDetectDeprecation(t *testing.T) {
+	tests := []struct {
+		name   string
+		api    string
+		header http.Header
+		want   *DeprecationInfo
+	}{
+		{
+			name: "not deprecated",
+			api:  `{"id":"a","deprecated":false}`,
+		},
+		{
+			name: "false string",
+			api:  `{"id":"a","deprecated":"False"}`,
+		},
+		{
+			name: "sunset field only",
+			api:  `{"id":"a","sunset":"2030-01-01"}`,
+		},
+		{
+			name: "field",
+			api:  `{"id":"a","deprecated":true}`,
+			want: &DeprecationInfo{Deprecated: true, Sources: []string{"field"}},
+		},
+		{
+			name: "field with date and sunset",
+			api:  `{"id":"a","deprecated":"2024-06-01","sunset_date":"2025-01-01"}`,
+			want: &DeprecationInfo{Deprecated: true, Sources: []string{"field"}, Since: "2024-06-01T00:00:00Z", Sunset: "2025-01-01T00:00:00Z"},
+		},
+		{
+			name:   "Deprecation header",
+			api:    `{"id":"a"}`,
+			header: http.Header{"Deprecation": {"@1688169599"}},
+			want:   &DeprecationInfo{Deprecated: true, Sources: []string{"deprecation_header"}, Since: "2023-06-30T23:59:59Z"},
+		},
+		{
+			name:   "false Deprecation header",
+			api:    `{"id":"a"}`,
+			header: http.Header{"Deprecation": {"false"}},
+		},
+		{
+			name:   "Sunset header",
+			api:    `{"id":"a"}`,
+			header: http.Header{"Sunset": {"Wed, 11 Nov 2026 23:59:59 GMT"}},
+			want:   &DeprecationInfo{Deprecated: true, Sources: []string{"sunset_header"}, Sunset: "2026-11-11T23:59:59Z"},
+		},
+		{
+			name: "all signals",
+			api:  `{"id":"a","deprecated":"2024-06-01","sunset":"2025-01-01"}`,
+			header: http.Header{
+				"Deprecation": {"@1688169599"},
+				"Sunset":      {"Wed, 11 Nov 2026 23:59:59 GMT"},
+			},
+			// The dates of the record take precedence.
+			want: &DeprecationInfo{
+				Deprecated: true,
+				Sources:    []string{"deprecation_header", "field", "sunset_header"},
+				Since:      "2024-06-01T00:00:00Z",
+				Sunset:     "2025-01-01T00:00:00Z",
+			},
+		},
+		{
+			name:   "malformed record",
+			api:    `[`,
+			header: http.Header{"Deprecation": {"true"}},
+			want:   &DeprecationInfo{Deprecated: true, Sources: []string{"deprecation_header"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectDeprecation(json.RawMessage(tt.api), tt.header)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("detectDeprecation() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDeprecationDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"@0", "1970-01-01T00:00:00Z"},
+		{"@1688169599", "2023-06-30T23:59:59Z"},
+		{"Wed, 11 Nov 2026 23:59:59 GMT", "2026-11-11T23:59:59Z"},
+		{"2026-11-11T23:59:59+01:00", "2026-11-11T22:59:59Z"},
+		{"2026-11-11", "2026-11-11T00:00:00Z"},
+		{"@soon", "@soon"},
+		{"next year", "next year"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDeprecationDate(tt.in); got != tt.want {
+			t.Errorf("formatDeprecationDate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	if got, want := deprecationWarning("a", &DeprecationInfo{Deprecated: true}),
+		"Warning: API a is deprecated. Consider using an alternative API."; got != want {
+		t.Errorf("deprecationWarning() = %q, want %q", got, want)
+	}
+	if got := deprecationWarning("a", &DeprecationInfo{Deprecated: true, Sunset: "2026-11-11T23:59:59Z"}); !strings.Contains(got, "will be sunset at 2026-11-11T23:59:59Z") {
+		t.Errorf("deprecationWarning() = %q, want the sunset date", got)
+	}
+}
+
+func TestGetAPIDeprecation(t *testing.T) {
+	t.Run("deprecated", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {
+				header: http.Header{"Sunset": {"Wed, 11 Nov 2026 23:59:59 GMT"}},
+				body:   `{"id":"a","deprecated":true}`,
+			},
+		})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+
+		if len(res.Content) != 2 {
+			t.Fatalf("got %d content items, want a warning and the API", len(res.Content))
+		}
+		warning := res.Content[0].(mcp.TextContent).Text
+		if !strings.HasPrefix(warning, "Warning: API a is deprecated and will be sunset at 2026-11-11T23:59:59Z") {
+			t.Errorf("warning = %q", warning)
+		}
+
+		var got struct {
+			Deprecation DeprecationInfo `json:"_deprecation"`
+			ID          string          `json:"id"`
+		}
+		decodeResult(t, res, &got)
+		want := DeprecationInfo{Deprecated: true, Sources: []string{"field", "sunset_header"}, Sunset: "2026-11-11T23:59:59Z"}
+		if !reflect.DeepEqual(got.Deprecation, want) {
+			t.Errorf("_deprecation = %+v, want %+v", got.Deprecation, want)
+		}
+		if got.ID != "a" {
+			t.Errorf("id = %q, want a", got.ID)
+		}
+	})
+
+	t.Run("not deprecated", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {body: `{"id":"a"}`},
+		})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+
+		if len(res.Content) != 1 {
+			t.Errorf("got %d content items, want the API only", len(res.Content))
+		}
+		if text := resultText(t, res); strings.Contains(text, "_deprecation") {
+			t.Errorf("result %q has _deprecation", text)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Version of the catalog snapshot token format.
+const snapshotVersion = 1
+
+// CatalogDiffParams represents the parameters for the catalogDiff tool.
+// The `snapshot` parameter is optional.
+type CatalogDiffParams struct {
+	Snapshot string `json:"snapshot,omitempty" jsonschema_description:"Snapshot returned by a previous call. Omit to take the first snapshot."`
+}
+
+// CatalogDiffResponse represents the response from the catalogDiff tool.
+type CatalogDiffResponse struct {
+	Added        []string `json:"added,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	Changed      []string `json:"changed,omitempty"`
+	Snapshot     string   `json:"snapshot"`
+	APICount     int      `json:"api_count"`
+	Skipped      int      `json:"skipped,omitempty"`
+	PagesScanned int      `json:"pages_scanned"`
+	Truncated    bool     `json:"truncated,omitempty"`
+}
+
+// catalogSnapshot is the decoded form of a snapshot token: a content hash per
+// API ID.
+type catalogSnapshot struct {
+	Version int               `json:"v"`
+	Hashes  map[string]string `json:"h"`
+}
+
+// createCatalogDiffTool creates a tool for computing the changes in the
+// catalog since a previous snapshot.
+func createCatalogDiffTool() mcp.Tool {
+	return createTool(mcp.ToolDef[CatalogDiffParams]{
+		Name: "catalog_diff",
+		Description: "Compute which APIs were added, removed or changed since a previous snapshot of the catalog. " +
+			"Pass the `snapshot` token returned by an earlier call; without it, only a new snapshot token is returned. " +
+			"When the traversal is truncated by the page budget, removals aren't reported. " +
+			"APIs without an ID can't be tracked; they're counted as `skipped`.",
+		HandleFunc: func(ctx context.Context, params CatalogDiffParams) *mcp.CallToolResult {
+			var prev *catalogSnapshot
+			if params.Snapshot != "" {
+				s, err := decodeSnapshot(params.Snapshot)
+				if err != nil {
+					return newToolCallErrorResult("Invalid snapshot token: %v", err)
+				}
+				prev = &s
+			}
+
+			current := catalogSnapshot{
+				Version: snapshotVersion,
+				Hashes:  make(map[string]string),
+			}
+
+			skipped := 0
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+				// Without an ID, an API can't be matched with its
+				// previous version.
+				id := apiID(rec)
+				if id == "" {
+					skipped++
+					return nil
+				}
+				hash, err := recordHash(item)
+				if err != nil {
+					return err
+				}
+				current.Hashes[id] = hash
+				return nil
+			})
+			if err != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", err)
+			}
+
+			token, err := encodeSnapshot(current)
+			if err != nil {
+				return newToolCallErrorResult("Error encoding snapshot: %v", err)
+			}
+
+			response := CatalogDiffResponse{
+				Snapshot:     token,
+				APICount:     len(current.Hashes),
+				Skipped:      skipped,
+				PagesScanned: pages,
+				Truncated:    more,
+			}
+
+			if prev != nil {
+				for id, hash := range current.Hashes {
+					prevHash, ok := prev.Hashes[id]
+					switch {
+					case !ok:
+						response.Added = append(response.Added, id)
+					case prevHash != hash:
+						response.Changed = append(response.Changed, id)
+					}
+				}
+				if !more {
+					for id := range prev.Hashes {
+						if _, ok := current.Hashes[id]; !ok {
+							response.Removed = append(response.Removed, id)
+						}
+					}
+				}
+				slices.Sort(response.Added)
+				slices.Sort(response.Changed)
+				slices.Sort(response.Removed)
+			}
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// recordHash returns a short, stable content hash of a JSON record.
+func recordHash(raw json.RawMessage) (string, error) {
+	canonical, err := canonicalJSON(raw)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// encodeSnapshot encodes a catalog snapshot as an opaque token.
+func encodeSnapshot(s catalogSnapshot) (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeSnapshot decodes a token created by encodeSnapshot.
+func decodeSnapshot(token string) (catalogSnapshot, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return catalogSnapshot{}, err
+	}
+
+	var s catalogSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return catalogSnapshot{}, err
+	}
+	if s.Version != snapshotVersion {
+		return catalogSnapshot{}, fmt.Errorf("unsupported version %d", s.Version)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestCatalogDiff(t *testing.T) {
+	catalog := `[{"id":"a","title":"A"},{"id":"b"},{"title":"Without ID"}]`
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fixture{body: catalog}.serve(w)
+	}))
+
+	var first CatalogDiffResponse
+	decodeResult(t, callTool(t, createCatalogDiffTool(), `{}`), &first)
+	if first.APICount != 2 || first.Skipped != 1 {
+		t.Errorf("first snapshot: api_count %d and skipped %d, want 2 and 1", first.APICount, first.Skipped)
+	}
+	if first.Added != nil || first.Removed != nil || first.Changed != nil {
+		t.Errorf("first snapshot reports changes: %+v", first)
+	}
+
+	// Records without an ID must not collapse onto a single entry, and
+	// must not show up as changed when there are several of them.
+	catalog = `[{"id":"a","title":"A2"},{"id":"c"},{"title":"Without ID"},{"title":"Also without ID"}]`
+
+	var got CatalogDiffResponse
+	decodeResult(t, callTool(t, createCatalogDiffTool(), `{"snapshot":"`+first.Snapshot+`"}`), &got)
+
+	if !slices.Equal(got.Added, []string{"c"}) {
+		t.Errorf("added = %q, want c", got.Added)
+	}
+	if !slices.Equal(got.Removed, []string{"b"}) {
+		t.Errorf("removed = %q, want b", got.Removed)
+	}
+	if !slices.Equal(got.Changed, []string{"a"}) {
+		t.Errorf("changed = %q, want a", got.Changed)
+	}
+	if got.APICount != 2 || got.Skipped != 2 {
+		t.Errorf("api_count %d and skipped %d, want 2 and 2", got.APICount, got.Skipped)
+	}
+
+	snapshot, err := decodeSnapshot(got.Snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snapshot.Hashes[""]; ok {
+		t.Error("snapshot has an entry for an empty ID")
+	}
+}
+
+func TestCatalogDiffInvalidSnapshot(t *testing.T) {
+	newFixtureServer(t, nil)
+
+	res := callTool(t, createCatalogDiffTool(), `{"snapshot":"not a token"}`)
+	expectError(t, res, "Invalid snapshot token")
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// errDryRun is returned for every outbound request in dry-run mode.
+var errDryRun = errors.New("request not sent: dry run")
+
+// dryRunContextKey is the context key for the dry-run request recorder of a
+// tool call.
+type dryRunContextKey struct{}
+
+// dryRunRecorder records the requests a tool call would have sent.
+type dryRunRecorder struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (r *dryRunRecorder) record(req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req.Method+" "+req.URL.String())
+}
+
+// dryRunTransport is an http.RoundTripper that never sends requests. Instead,
+// it records them with the recorder of the tool call, if any, and fails with
+// errDryRun.
+type dryRunTransport struct{}
+
+func (dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rec, ok := req.Context().Value(dryRunContextKey{}).(*dryRunRecorder); ok {
+		rec.record(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, errDryRun
+}
+
+// handleDryRun calls a tool handler in dry-run mode. If the handler attempted
+// an upstream request, its result is replaced by the URL of that request, so
+// the URL-building logic of tools can be checked in isolation. Tools that
+// don't make upstream requests return their regular result.
+func handleDryRun[T any](ctx context.Context, handle func(context.Context, T) *mcp.CallToolResult, params T) *mcp.CallToolResult {
+	rec := &dryRunRecorder{}
+	result := handle(context.WithValue(ctx, dryRunContextKey{}, rec), params)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.requests) == 0 {
+		return result
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Text: "Dry run, no request was sent. The tool would fetch:\n" + strings.Join(rec.requests, "\n"),
+			},
+		},
+	}
+}
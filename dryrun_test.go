@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDryRun(t *testing.T) {
+	srv, requests := newFixtureServer(t, map[string]fixture{
+		"/apis/a": {body: `{"id":"a"}`},
+	})
+
+	oldDryRun, oldLimit, oldBurst, oldTimeout := dryRun, rateLimit, rateBurst, requestTimeout
+	t.Cleanup(func() {
+		dryRun, rateLimit, rateBurst, requestTimeout = oldDryRun, oldLimit, oldBurst, oldTimeout
+	})
+	// With a burst of 1, a second call would have to wait far past the
+	// timeout if dry-run requests went through the rate limiter.
+	dryRun, rateLimit, rateBurst, requestTimeout = true, 0.001, 1, time.Second
+	httpClient = newUpstreamClient(http.DefaultTransport.(*http.Transport).Clone(), slog.New(slog.DiscardHandler))
+
+	tool := createGetAPITool(httpClient, apiBaseURL)
+	for range 3 {
+		res := callTool(t, tool, `{"id":"a"}`)
+		text := resultText(t, res)
+		if res.IsError {
+			t.Fatalf("unexpected error result: %v", text)
+		}
+		if want := "GET " + srv.URL + "/apis/a"; !strings.Contains(text, want) {
+			t.Errorf("result %q doesn't contain %q", text, want)
+		}
+	}
+
+	if len(*requests) != 0 {
+		t.Errorf("got %d upstream requests in dry-run mode, want 0", len(*requests))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// Error categories, reported with tool call errors so clients can decide
+// whether retrying makes sense.
+var (
+	// errNetwork is the category of failures to reach an upstream server,
+	// e.g. DNS or connection errors.
+	errNetwork = errors.New("network error")
+	// errUpstream is the category of error responses from an upstream
+	// server, e.g. a 5xx status or an HTML error page.
+	errUpstream = errors.New("upstream error")
+	// errDecode is the category of upstream responses that can't be parsed.
+	errDecode = errors.New("decode error")
+)
+
+// categoryError is an error belonging to one of the error categories. Its
+// message is that of the underlying error.
+type categoryError struct {
+	category error
+	err      error
+}
+
+func (e categoryError) Error() string {
+	return e.err.Error()
+}
+
+func (e categoryError) Unwrap() []error {
+	return []error{e.category, e.err}
+}
+
+// statusError is an error caused by an upstream response with a particular
+// status code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e statusError) Unwrap() error {
+	return e.err
+}
+
+// withCategory returns err as belonging to category, or nil if err is nil.
+func withCategory(category, err error) error {
+	if err == nil {
+		return nil
+	}
+	return categoryError{category: category, err: err}
+}
+
+// errorCategory returns the name of the category of err: "network",
+// "upstream", "decode", "timeout" or "canceled". It returns an empty string
+// for errors that don't belong to a category, such as invalid parameters.
+func errorCategory(err error) string {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		urlErr    *url.Error
+		netErr    net.Error
+	)
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, errDecode), errors.As(err, &syntaxErr), errors.As(err, &typeErr),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return "decode"
+	case errors.Is(err, errUpstream):
+		return "upstream"
+	case errors.Is(err, errNetwork), errors.As(err, &urlErr), errors.As(err, &netErr):
+		return "network"
+	}
+
+	return ""
+}
+
+// errorHint returns a remediation hint for err, based on the upstream status
+// code or the error category, or an empty string if there's none.
+func errorHint(err error) string {
+	var se statusError
+	if errors.As(err, &se) {
+		switch {
+		case se.code == http.StatusNotFound:
+			return "check the id; list or search tools return valid ids"
+		case se.code == http.StatusUnauthorized || se.code == http.StatusForbidden:
+			return "the API register requires authentication, see -auth-token"
+		case se.code == http.StatusTooManyRequests:
+			return "the API register is rate limiting requests, wait a moment and try again"
+		case se.code >= 500:
+			return "the API register may be down, try again later"
+		case se.code >= 400:
+			return "check the parameters of the tool call"
+		}
+	}
+
+	switch errorCategory(err) {
+	case "network":
+		return "the API register couldn't be reached, check the network connection (or -proxy) and try again"
+	case "decode":
+		return "the API register returned an unexpected response, try again later"
+	case "timeout":
+		return "the request took too long, try again, e.g. with a smaller page size"
+	}
+
+	return ""
+}
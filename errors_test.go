@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestErrorCategory(t *testing.T) {
+	var syntaxErr error
+	if err := json.Unmarshal([]byte(`{`), new(any)); err != nil {
+		syntaxErr = err
+	}
+	var typeErr error
+	if err := json.Unmarshal([]byte(`"a"`), new(int)); err != nil {
+		typeErr = err
+	}
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"deadline", fmt.Errorf("fetching: %w", context.DeadlineExceeded), "timeout"},
+		{"canceled", fmt.Errorf("fetching: %w", context.Canceled), "canceled"},
+		{"decode category", withCategory(errDecode, errors.New("bad body")), "decode"},
+		{"JSON syntax error", syntaxErr, "decode"},
+		{"JSON type error", typeErr, "decode"},
+		{"truncated body", fmt.Errorf("reading: %w", io.ErrUnexpectedEOF), "decode"},
+		{"upstream category", withCategory(errUpstream, statusError{code: 500, err: errors.New("upstream returned 500")}), "upstream"},
+		{"network category", withCategory(errNetwork, errors.New("connection reset")), "network"},
+		{"URL error", &url.Error{Op: "Get", URL: "https://example.com", Err: errors.New("EOF")}, "network"},
+		{"net error", dialErr, "network"},
+		{"timeout over network", &url.Error{Op: "Get", URL: "https://example.com", Err: context.DeadlineExceeded}, "timeout"},
+		{"uncategorized", errors.New("id parameter is required"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCategory(tt.err); got != tt.want {
+				t.Errorf("errorCategory(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorHint(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{statusError{code: http.StatusNotFound, err: errors.New("not found")}, "check the id"},
+		{statusError{code: http.StatusUnauthorized, err: errors.New("unauthorized")}, "-auth-token"},
+		{statusError{code: http.StatusTooManyRequests, err: errors.New("rate limited")}, "rate limiting"},
+		{statusError{code: http.StatusBadGateway, err: errors.New("bad gateway")}, "may be down"},
+		{statusError{code: http.StatusBadRequest, err: errors.New("bad request")}, "check the parameters"},
+		{withCategory(errNetwork, errors.New("connection reset")), "couldn't be reached"},
+		{withCategory(errDecode, errors.New("bad body")), "unexpected response"},
+		{context.DeadlineExceeded, "took too long"},
+		{errors.New("other"), ""},
+	}
+
+	for _, tt := range tests {
+		got := errorHint(tt.err)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("errorHint(%v) = %q, want it to contain %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestErrorResultCategory(t *testing.T) {
+	t.Run("upstream", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusServiceUnavailable, body: "maintenance"},
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[upstream] Error fetching APIs")
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {body: `[{`},
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[decode] Error fetching APIs")
+	})
+
+	t.Run("network", func(t *testing.T) {
+		srv := newTestServer(t, http.NotFoundHandler())
+		srv.Close()
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[network] Error fetching APIs")
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		newFixtureServer(t, nil)
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"  "}`)
+		if text := resultText(t, res); strings.HasPrefix(text, "[") {
+			t.Errorf("invalid parameter error %q has a category", text)
+		}
+	})
+
+	t.Run("hint", func(t *testing.T) {
+		old := prettyErrors
+		t.Cleanup(func() { prettyErrors = old })
+		prettyErrors = true
+		newFixtureServer(t, nil)
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+		expectError(t, res, "Hint: check the id")
+	})
+}
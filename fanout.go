@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Default maximum number of concurrent upstream fetches made by a single tool
+// call that fans out (e.g. fetching the APIs linked to a repository).
+const defaultFetchConcurrency = 4
+
+// fetchConcurrent calls fetch for each key, running at most fetchConcurrency
+// calls at once. Results and errors are returned in the order of keys,
+// regardless of the order in which the calls complete. A failed call doesn't
+// abort the others; once ctx is done, the remaining keys fail with its error.
+func fetchConcurrent[T any](ctx context.Context, keys []string, fetch func(context.Context, string) (T, error)) ([]T, []error) {
+	results := make([]T, len(keys))
+	errs := make([]error, len(keys))
+
+	var g errgroup.Group
+	g.SetLimit(max(fetchConcurrency, 1))
+	for i, key := range keys {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return nil
+			}
+			results[i], errs[i] = fetch(ctx, key)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results, errs
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchConcurrent(t *testing.T) {
+	prev := fetchConcurrency
+	t.Cleanup(func() { fetchConcurrency = prev })
+	fetchConcurrency = 3
+
+	keys := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	errFailed := errors.New("failed")
+
+	var inFlight, maxInFlight atomic.Int32
+	results, errs := fetchConcurrent(context.Background(), keys, func(ctx context.Context, key string) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		// Finish in reverse order of the keys, so results are out of order
+		// unless they're stored by index.
+		time.Sleep(time.Duration(len(keys)-int(key[0]-'a')) * 5 * time.Millisecond)
+		if key == "c" {
+			return "", errFailed
+		}
+		return "result " + key, nil
+	})
+
+	for i, key := range keys {
+		if key == "c" {
+			if !errors.Is(errs[i], errFailed) {
+				t.Errorf("error for %q = %v, want %v", key, errs[i], errFailed)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Errorf("unexpected error for %q: %v", key, errs[i])
+		}
+		if want := "result " + key; results[i] != want {
+			t.Errorf("results[%d] = %q, want %q", i, results[i], want)
+		}
+	}
+	if got := maxInFlight.Load(); got > 3 {
+		t.Errorf("max in-flight fetches = %d, want at most 3", got)
+	}
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("max in-flight fetches = %d, want fetches to run concurrently", got)
+	}
+}
+
+func TestFetchConcurrentCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	_, errs := fetchConcurrent(ctx, []string{"a", "b"}, func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		return key, nil
+	})
+	if calls.Load() != 0 {
+		t.Errorf("fetch called %d times after cancellation", calls.Load())
+	}
+	for i, err := range errs {
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("errs[%d] = %v, want %v", i, err, context.Canceled)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Limits for the number of APIs returned by the advancedListAPIs tool.
+const (
+	defaultFilterLimit = 100
+	maxFilterLimit     = 500
+)
+
+// Combine modes for the criteria of an APIFilter.
+const (
+	combineAnd = "and"
+	combineOr  = "or"
+)
+
+// APIFilter represents a structured filter on APIs. Empty criteria are ignored.
+type APIFilter struct {
+	Organizations []string
+	Tags          []string
+	Type          string
+	Query         string
+}
+
+// AdvancedListAPIsParams represents the parameters for the advancedListAPIs tool.
+// All parameters are optional. The criteria are flat, and lists are
+// comma-separated strings, because go-mcp can't validate object or array
+// parameters. The `combine` parameter defaults to "and".
+type AdvancedListAPIsParams struct {
+	Organizations string `json:"organizations,omitempty" jsonschema_description:"Comma-separated organization names. Only match APIs of one of these organizations."`
+	Tags          string `json:"tags,omitempty" jsonschema_description:"Comma-separated tags. Only match APIs with one of these tags."`
+	Type          string `json:"type,omitempty" jsonschema_description:"Only match APIs of this type (see list_api_types)."`
+	Query         string `json:"query,omitempty" jsonschema_description:"Only match APIs whose title or description contains this text."`
+	Combine       string `json:"combine,omitempty" jsonschema:"enum=and,enum=or" jsonschema_description:"How criteria are combined: and or or. Defaults to and."`
+	Limit         int    `json:"limit,omitempty" jsonschema_description:"Maximum number of APIs returned, at most 500. Defaults to 100."`
+}
+
+// filter returns the APIFilter described by the parameters.
+func (p AdvancedListAPIsParams) filter() APIFilter {
+	return APIFilter{
+		Organizations: splitList(p.Organizations),
+		Tags:          splitList(p.Tags),
+		Type:          strings.TrimSpace(p.Type),
+		Query:         strings.TrimSpace(p.Query),
+	}
+}
+
+// AdvancedListAPIsResponse represents the response from the advancedListAPIs tool.
+type AdvancedListAPIsResponse struct {
+	APIs         []json.RawMessage `json:"apis"`
+	Count        int               `json:"count"`
+	PagesScanned int               `json:"pages_scanned"`
+	Truncated    bool              `json:"truncated,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// createAdvancedListAPIsTool creates a tool for listing APIs matching a
+// structured filter.
+func createAdvancedListAPIsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[AdvancedListAPIsParams]{
+		Name: "advanced_list_apis",
+		Description: "List APIs matching a structured filter (organizations, tags, type, free-text query). " +
+			"`organizations` and `tags` take comma-separated values. " +
+			`Criteria are combined with "and" (default) or "or". Each list criterion matches if any of its values match. ` +
+			"Results are deduplicated by ID; the number of pages scanned is bounded.",
+		HandleFunc: func(ctx context.Context, params AdvancedListAPIsParams) *mcp.CallToolResult {
+			combine := strings.ToLower(params.Combine)
+			if combine == "" {
+				combine = combineAnd
+			}
+			if combine != combineAnd && combine != combineOr {
+				return newToolCallErrorResult("Invalid combine mode %q, must be %q or %q", params.Combine, combineAnd, combineOr)
+			}
+
+			limit := params.Limit
+			if limit <= 0 {
+				limit = defaultFilterLimit
+			}
+			limit = min(limit, maxFilterLimit)
+
+			filter := params.filter()
+			response := AdvancedListAPIsResponse{
+				APIs: []json.RawMessage{},
+			}
+
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+				if !matchAPI(rec, filter, combine) {
+					return nil
+				}
+				if len(response.APIs) == limit {
+					response.Truncated = true
+					return errStopWalk
+				}
+				item, err := normalizeRecords(item)
+				if err != nil {
+					return err
+				}
+				response.APIs = append(response.APIs, item)
+				return nil
+			})
+			if err != nil {
+				// A timed out page still yields the results gathered so far.
+				if !errors.Is(err, errPageTimeout) {
+					return newToolCallErrorResult("Error fetching APIs: %v", err)
+				}
+				response.Error = err.Error()
+				more = true
+			}
+
+			response.Count = len(response.APIs)
+			response.PagesScanned = pages
+			response.Truncated = response.Truncated || more
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// matchAPI reports whether a decoded API record matches the filter, combining
+// the non-empty criteria using the given mode.
+func matchAPI(rec map[string]any, filter APIFilter, combine string) bool {
+	var results []bool
+
+	if len(filter.Organizations) > 0 {
+		org := apiOrganization(rec)
+		results = append(results, slices.ContainsFunc(filter.Organizations, func(o string) bool {
+			return org != "" && strings.EqualFold(org, o)
+		}))
+	}
+	if len(filter.Tags) > 0 {
+		tags := recordStrings(rec, "tags")
+		results = append(results, slices.ContainsFunc(filter.Tags, func(t string) bool {
+			return slices.ContainsFunc(tags, func(tag string) bool { return strings.EqualFold(tag, t) })
+		}))
+	}
+	if filter.Type != "" {
+		results = append(results, strings.EqualFold(apiType(rec), filter.Type))
+	}
+	if filter.Query != "" {
+		results = append(results, containsFold(apiName(rec), filter.Query) ||
+			containsFold(recordString(rec, "description"), filter.Query))
+	}
+
+	if len(results) == 0 {
+		return true
+	}
+	if combine == combineOr {
+		return slices.Contains(results, true)
+	}
+	return !slices.Contains(results, false)
+}
+
+// filterByOrganization returns the APIs in a list response body that belong to
+// the organization with the given name (case-insensitive), as a JSON array.
+func filterByOrganization(body json.RawMessage, org string) (json.RawMessage, error) {
+	items, err := decodeItems(body)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := APIFilter{Organizations: []string{org}}
+	matches := []json.RawMessage{}
+	for _, item := range items {
+		var rec map[string]any
+		if err := json.Unmarshal(item, &rec); err != nil {
+			return nil, err
+		}
+		if matchAPI(rec, filter, combineAnd) {
+			matches = append(matches, item)
+		}
+	}
+
+	return json.Marshal(matches)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAdvancedListAPIs(t *testing.T) {
+	const apis = `[
+		{"id":"a","title":"Kadaster BAG","organization":{"name":"Kadaster"},"tags":["adressen"],"type":"rest_json"},
+		{"id":"b","title":"Kadaster BRK","organization":{"name":"Kadaster"},"tags":["percelen"],"type":"rest_json"},
+		{"id":"c","title":"RDW voertuigen","organization":{"name":"RDW"},"tags":["voertuigen"],"type":"rest_json"},
+		{"id":"d","title":"KvK handelsregister","organization":{"name":"KvK"},"tags":["adressen"],"type":"soap_xml"}
+	]`
+
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"no criteria", `{}`, []string{"a", "b", "c", "d"}},
+		{"organizations", `{"organizations":"kadaster, RDW"}`, []string{"a", "b", "c"}},
+		{"and", `{"organizations":"Kadaster","tags":"adressen"}`, []string{"a"}},
+		{"or", `{"organizations":"Kadaster","tags":"adressen","combine":"or"}`, []string{"a", "b", "d"}},
+		{"type and query", `{"type":"rest_json","query":"voertuig"}`, []string{"c"}},
+		{"limit", `{"limit":2}`, []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newFixtureServer(t, map[string]fixture{
+				"/apis": {body: apis},
+			})
+
+			res := callTool(t, createAdvancedListAPIsTool(), tt.args)
+
+			var got struct {
+				APIs []struct {
+					ID string `json:"id"`
+				} `json:"apis"`
+				Count int `json:"count"`
+			}
+			decodeResult(t, res, &got)
+
+			var ids []string
+			for _, api := range got.APIs {
+				ids = append(ids, api.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("got APIs %q, want %q", ids, tt.want)
+			}
+			if got.Count != len(tt.want) {
+				t.Errorf("count = %d, want %d", got.Count, len(tt.want))
+			}
+		})
+	}
+
+	t.Run("invalid combine mode", func(t *testing.T) {
+		res := callTool(t, createAdvancedListAPIsTool(), `{"combine":"xor"}`)
+		expectError(t, res, "Invalid combine mode")
+	})
+}
+
+func TestListAPIsOrganizationFilter(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis": listPageFixture("/apis", `[
+			{"id":"a","organization":{"name":"Kadaster"}},
+			{"id":"b","organization":{"name":"RDW"}},
+			{"id":"c","organization":{"name":"kadaster"}},
+			{"id":"d"}
+		]`, 2, 0, 2, 8),
+	})
+
+	tests := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"omitted", `{}`, []string{"a", "b", "c", "d"}},
+		{"empty", `{"organization":""}`, []string{"a", "b", "c", "d"}},
+		{"whitespace", `{"organization":"  "}`, []string{"a", "b", "c", "d"}},
+		{"case-insensitive", `{"organization":" KADASTER "}`, []string{"a", "c"}},
+		{"no match", `{"organization":"KvK"}`, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), tt.args)
+
+			var got struct {
+				APIs []struct {
+					ID string `json:"id"`
+				} `json:"apis"`
+				listPageResult
+			}
+			decodeResult(t, res, &got)
+
+			if got.APIs == nil {
+				t.Fatalf("apis = null in %v, want a list", resultText(t, res))
+			}
+			ids := []string{}
+			for _, api := range got.APIs {
+				ids = append(ids, api.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("APIs = %q, want %q", ids, tt.want)
+			}
+			// Filtering applies to the fetched page only, so the upstream
+			// pagination still applies.
+			if got.NextPage != 2 {
+				t.Errorf("next page = %d, want 2", got.NextPage)
+			}
+		})
+	}
+}
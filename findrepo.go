@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// FindRepositoryParams represents the parameters for the findRepository tool.
+// The `url` parameter is required.
+type FindRepositoryParams struct {
+	URL string `json:"url" jsonschema:"required" jsonschema_description:"Source URL of the repository, e.g. https://github.com/owner/repo or git@github.com:owner/repo.git."`
+}
+
+// createFindRepositoryTool creates a tool for finding a repository by its
+// source URL.
+func createFindRepositoryTool() mcp.Tool {
+	return createTool(mcp.ToolDef[FindRepositoryParams]{
+		Name: "find_repository",
+		Description: "Find a repository in the Developer Overheid API by its source URL (e.g. a GitHub or GitLab " +
+			"URL). URLs are compared after normalization, ignoring the scheme, `www.`, a trailing slash and a " +
+			"`.git` suffix, so HTTPS and SSH URLs match. Searches the repositories; the number of pages " +
+			"scanned is bounded.",
+		HandleFunc: func(ctx context.Context, params FindRepositoryParams) *mcp.CallToolResult {
+			_, want, err := parseSourceURL(params.URL)
+			if err != nil {
+				return newToolCallErrorResult("Invalid url: %v", err)
+			}
+
+			var match map[string]any
+			_, more, err := walkPages(ctx, "repositories", maxPages, func(item json.RawMessage) error {
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+				host, webURL, err := parseSourceURL(recordString(rec, "url", "repository_url", "source_url"))
+				if err != nil || !strings.EqualFold(webURL, want) {
+					return nil
+				}
+				rec["source_host"] = host
+				rec["web_url"] = webURL
+				match = rec
+				return errStopWalk
+			})
+			if err != nil {
+				return newToolCallErrorResult("Error fetching repositories: %v", err)
+			}
+
+			switch {
+			case match == nil && more:
+				return newToolCallErrorResult("No repository found with URL %v in the first %d pages of repositories", want, maxPages)
+			case match == nil:
+				return newToolCallErrorResult("No repository found with URL %v", want)
+			}
+
+			result, err := marshalResult(normalizeValue(match), prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
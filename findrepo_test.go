@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestFindRepository(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/repositories": listPageFixture("/repositories", `[
+			{"id":"a","url":"https://gitlab.com/owner/repo"},
+			{"id":"b","repository_url":"git@github.com:owner/repo.git"},
+			{"id":"c","source_url":"https://github.com/owner/repo-two/"}
+		]`, 0, 0, 0, 0),
+	})
+
+	tests := []struct {
+		url    string
+		wantID string
+	}{
+		{"https://github.com/owner/repo", "b"},
+		{"https://github.com/Owner/Repo", "b"},
+		{"http://www.github.com/owner/repo/", "b"},
+		{"git@github.com:owner/repo.git", "b"},
+		{"ssh://git@github.com:22/owner/repo.git", "b"},
+		{"https://gitlab.com/owner/repo.git", "a"},
+		{"git@github.com:owner/repo-two", "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			var got struct {
+				ID         string `json:"id"`
+				SourceHost string `json:"source_host"`
+				WebURL     string `json:"web_url"`
+			}
+			decodeResult(t, callTool(t, createFindRepositoryTool(), `{"url":"`+tt.url+`"}`), &got)
+			if got.ID != tt.wantID {
+				t.Errorf("found repository %q, want %q", got.ID, tt.wantID)
+			}
+			if got.SourceHost == "" || got.WebURL == "" {
+				t.Errorf("source host = %q, web URL = %q; want both set", got.SourceHost, got.WebURL)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		res := callTool(t, createFindRepositoryTool(), `{"url":"https://github.com/owner/other"}`)
+		expectError(t, res, "No repository found with URL https://github.com/owner/other")
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		res := callTool(t, createFindRepositoryTool(), `{"url":"ftp://github.com/owner/repo"}`)
+		expectError(t, res, `unsupported URL scheme "ftp"`)
+	})
+}
+
+func TestFindRepositoryPageLimit(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/repositories": listPageFixture("/repositories", `[{"id":"a","url":"https://github.com/owner/a"}]`, 2, 0, 2, 0),
+	})
+	maxPages = 1
+
+	res := callTool(t, createFindRepositoryTool(), `{"url":"https://github.com/owner/b"}`)
+	expectError(t, res, "in the first 1 pages of repositories")
+}
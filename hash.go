@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// HashAPIParams represents the parameters for the hashAPI tool.
+// The `id` parameter is required.
+type HashAPIParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// HashAPIResponse represents the response from the hashAPI tool.
+type HashAPIResponse struct {
+	ID              string `json:"id"`
+	Algorithm       string `json:"algorithm"`
+	Hash            string `json:"hash"`
+	CanonicalLength int    `json:"canonical_length"`
+}
+
+// createHashAPITool creates a tool for computing a stable content hash of an
+// API record.
+func createHashAPITool() mcp.Tool {
+	return createTool(mcp.ToolDef[HashAPIParams]{
+		Name: "hash_api",
+		Description: "Compute a stable SHA-256 content hash of an API record by ID, for cheap change detection " +
+			"between runs. The record is canonicalized (sorted keys, no insignificant whitespace) before " +
+			"hashing, so the hash doesn't depend on upstream key ordering.",
+		HandleFunc: func(ctx context.Context, params HashAPIParams) *mcp.CallToolResult {
+			raw, err := fetchAPIRaw(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			canonical, err := canonicalJSON(raw)
+			if err != nil {
+				return newToolCallErrorResult("Error canonicalizing API record: %v", err)
+			}
+			sum := sha256.Sum256(canonical)
+
+			result, err := marshalResult(HashAPIResponse{
+				ID:              params.ID,
+				Algorithm:       "sha256",
+				Hash:            hex.EncodeToString(sum[:]),
+				CanonicalLength: len(canonical),
+			}, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestHashAPI(t *testing.T) {
+	hash := func(t *testing.T, record string) HashAPIResponse {
+		t.Helper()
+
+		newFixtureServer(t, map[string]fixture{"/apis/a": {body: record}})
+		res := callTool(t, createHashAPITool(), `{"id":"a"}`)
+
+		var got HashAPIResponse
+		decodeResult(t, res, &got)
+		return got
+	}
+
+	want := hash(t, `{"id":"a","title":"A","version":1.50,"organization":{"name":"Org","ooid":1},"tags":["x","y"]}`)
+	if want.Algorithm != "sha256" || len(want.Hash) != 64 {
+		t.Fatalf("got %+v, want a hex SHA-256 hash", want)
+	}
+
+	permutations := []string{
+		`{"tags":["x","y"],"organization":{"ooid":1,"name":"Org"},"version":1.50,"title":"A","id":"a"}`,
+		`{
+			"organization": {"name": "Org", "ooid": 1},
+			"id": "a",
+			"version": 1.50,
+			"tags": ["x", "y"],
+			"title": "A"
+		}`,
+	}
+	for _, record := range permutations {
+		if got := hash(t, record); got != want {
+			t.Errorf("hash of %s = %+v, want %+v", record, got, want)
+		}
+	}
+
+	changes := []string{
+		// Array order is significant.
+		`{"id":"a","title":"A","version":1.50,"organization":{"name":"Org","ooid":1},"tags":["y","x"]}`,
+		// Numbers are hashed verbatim.
+		`{"id":"a","title":"A","version":1.5,"organization":{"name":"Org","ooid":1},"tags":["x","y"]}`,
+		`{"id":"a","title":"B","version":1.50,"organization":{"name":"Org","ooid":1},"tags":["x","y"]}`,
+	}
+	for _, record := range changes {
+		if got := hash(t, record); got.Hash == want.Hash {
+			t.Errorf("hash of %s equals the hash of the original record", record)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// headerFlag is a repeatable flag of `Name: value` request headers.
+type headerFlag struct {
+	header http.Header
+}
+
+// String returns the headers, with the values of credential headers redacted.
+func (f *headerFlag) String() string {
+	if f == nil || len(f.header) == 0 {
+		return ""
+	}
+	var values []string
+	for name, vals := range f.header {
+		for _, v := range vals {
+			if isCredentialHeader(name) {
+				v = "REDACTED"
+			}
+			values = append(values, name+": "+v)
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// isCredentialHeader reports whether the values of the (canonical) header
+// name hold credentials, which must never be logged.
+func isCredentialHeader(name string) bool {
+	switch name {
+	case "Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key":
+		return true
+	}
+	return false
+}
+
+// upstreamHeaders returns the headers to send with every request to the
+// Developer Overheid API: the configured headers, plus an `Authorization`
+// header for the bearer token, if set.
+func upstreamHeaders(header http.Header, authToken string) http.Header {
+	header = header.Clone()
+	if authToken != "" {
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set("Authorization", "Bearer "+authToken)
+	}
+	return header
+}
+
+// Set parses a `Name: value` header and adds it to the flag's headers.
+func (f *headerFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return fmt.Errorf("invalid header %q, must be formatted as \"Name: value\"", s)
+	}
+	if strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("invalid header name %q", name)
+	}
+
+	if f.header == nil {
+		f.header = make(http.Header)
+	}
+	f.header.Add(textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(value))
+	return nil
+}
+
+// headerTransport is an http.RoundTripper that adds a fixed set of headers to
+// every request to host, e.g. for gateways requiring an API key. Requests to
+// other hosts (such as servers of OpenAPI documents) are sent unchanged, so the
+// headers don't leak to third parties. Headers set on a request itself take
+// precedence.
+type headerTransport struct {
+	next   http.RoundTripper
+	host   string
+	header http.Header
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != t.host {
+		return t.next.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		if _, ok := req.Header[name]; ok {
+			continue
+		}
+		req.Header[name] = values
+	}
+	return t.next.RoundTrip(req)
+}
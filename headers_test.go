@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestHeaderTransport(t *testing.T) {
+	// newServer starts a server recording the headers of the last request.
+	newServer := func(t *testing.T) (*httptest.Server, *http.Header) {
+		var got http.Header
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Clone()
+		}))
+		t.Cleanup(srv.Close)
+		return srv, &got
+	}
+	apiServer, apiHeaders := newServer(t)
+	otherServer, otherHeaders := newServer(t)
+
+	var flag headerFlag
+	for _, h := range []string{"X-Api-Key: secret", "X-Client: mcp", "x-client: second"} {
+		if err := flag.Set(h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	u, _ := url.Parse(apiServer.URL)
+	rt := headerTransport{next: http.DefaultTransport, host: u.Host, header: upstreamHeaders(flag.header, "token")}
+
+	send := func(t *testing.T, rawURL string, header http.Header) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	t.Run("API host", func(t *testing.T) {
+		send(t, apiServer.URL+"/apis", nil)
+
+		want := map[string][]string{
+			"X-Api-Key":     {"secret"},
+			"X-Client":      {"mcp", "second"},
+			"Authorization": {"Bearer token"},
+		}
+		for name, values := range want {
+			if got := (*apiHeaders).Values(name); !slices.Equal(got, values) {
+				t.Errorf("%v = %q, want %q", name, got, values)
+			}
+		}
+	})
+
+	t.Run("request headers take precedence", func(t *testing.T) {
+		send(t, apiServer.URL+"/apis", http.Header{"X-Client": {"override"}})
+
+		if got := (*apiHeaders).Values("X-Client"); !slices.Equal(got, []string{"override"}) {
+			t.Errorf("X-Client = %q, want the request's own value", got)
+		}
+	})
+
+	t.Run("other hosts", func(t *testing.T) {
+		send(t, otherServer.URL+"/openapi.json", nil)
+
+		for _, name := range []string{"X-Api-Key", "X-Client", "Authorization"} {
+			if got := (*otherHeaders).Get(name); got != "" {
+				t.Errorf("%v = %q sent to another host, want none", name, got)
+			}
+		}
+	})
+}
+
+func TestHeaderFlag(t *testing.T) {
+	var flag headerFlag
+	for _, invalid := range []string{"no colon", ": value", "Bad Name: value"} {
+		if err := flag.Set(invalid); err == nil {
+			t.Errorf("Set(%q) succeeded, want an error", invalid)
+		}
+	}
+
+	if err := flag.Set("Authorization:  Bearer abc "); err != nil {
+		t.Fatal(err)
+	}
+	if got := flag.header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("Authorization = %q, want the trimmed value", got)
+	}
+	if got := flag.String(); got != "Authorization: REDACTED" {
+		t.Errorf("String() = %q, want the value redacted", got)
+	}
+}
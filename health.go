@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Settings for the upstream reachability probe.
+const (
+	probeTimeout  = 3 * time.Second
+	probeCacheTTL = 10 * time.Second
+)
+
+// Probe of the upstream register, shared by the readiness endpoint and the
+// pingUpstream tool.
+var upstream = newUpstreamProbe()
+
+// upstreamProbe checks whether the upstream register is reachable. Results are
+// cached briefly, so that frequent health checks don't hammer the upstream.
+type upstreamProbe struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	result ProbeResult
+	err    error
+}
+
+// ProbeResult represents the result of probing the upstream register. It's
+// the response body of the readiness endpoint, and the response from the
+// pingUpstream tool.
+type ProbeResult struct {
+	Status    string    `json:"status"`
+	Reason    string    `json:"reason,omitempty"`
+	LatencyMS int64     `json:"latency_ms"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// PingUpstreamParams represents the parameters for the pingUpstream tool.
+type PingUpstreamParams struct{}
+
+// newUpstreamProbe returns an upstream probe with the default settings.
+func newUpstreamProbe() *upstreamProbe {
+	return &upstreamProbe{
+		timeout:  probeTimeout,
+		cacheTTL: probeCacheTTL,
+	}
+}
+
+// check returns the (possibly cached) result of probing the upstream, and an
+// error if it isn't reachable.
+func (p *upstreamProbe) check(ctx context.Context) (ProbeResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.result.CheckedAt.IsZero() && time.Since(p.result.CheckedAt) < p.cacheTTL {
+		return p.result, p.err
+	}
+
+	start := time.Now()
+	p.err = p.probe(ctx)
+	p.result = ProbeResult{
+		Status:    "ok",
+		LatencyMS: time.Since(start).Milliseconds(),
+		CheckedAt: start,
+	}
+	if p.err != nil {
+		p.result.Status = "unavailable"
+		p.result.Reason = p.err.Error()
+	}
+
+	return p.result, p.err
+}
+
+// probe issues a lightweight HEAD request for the APIs list, bounded by the
+// probe's own timeout. Any response other than a server error counts as
+// reachable. The result is shared by all waiting callers, so it isn't tied to
+// the cancellation of the calling request.
+func (p *upstreamProbe) probe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), p.timeout)
+	defer cancel()
+
+	apiURL, err := joinURL(apiBaseURL, "apis")
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("upstream returned %v", resp.Status)
+	}
+
+	return nil
+}
+
+// handleHealthz reports that the process is alive. It doesn't depend on the
+// upstream register, so that an upstream outage doesn't get the server
+// restarted; readiness is reported by handleStatus.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write([]byte(`{"status":"ok"}` + "\n"))
+}
+
+// handleStatus reports whether the upstream register is reachable, which the
+// server needs to serve tool calls. It responds with 200 when reachable and
+// 503 otherwise, with a JSON body stating the latency and the reason.
+func (p *upstreamProbe) handleStatus(w http.ResponseWriter, r *http.Request) {
+	result, err := p.check(r.Context())
+
+	status := http.StatusOK
+	if err != nil {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		slog.ErrorContext(r.Context(), "Failed to write health response", "error", err)
+	}
+}
+
+// createPingUpstreamTool creates a tool for checking whether the upstream
+// register is reachable.
+func createPingUpstreamTool() mcp.Tool {
+	return createTool(mcp.ToolDef[PingUpstreamParams]{
+		Name: "ping_upstream",
+		Description: "Check whether the Developer Overheid API is reachable, and report the latency of the check. " +
+			"Results are cached for 10 seconds.",
+		HandleFunc: func(ctx context.Context, params PingUpstreamParams) *mcp.CallToolResult {
+			probeResult, _ := upstream.check(ctx)
+
+			result, err := marshalResult(probeResult, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
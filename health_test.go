@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHealthEndpoints(t *testing.T) {
+	tests := []struct {
+		name          string
+		upstream      fixture
+		wantReadiness int
+	}{
+		{"healthy upstream", fixture{}, http.StatusOK},
+		{"unhealthy upstream", fixture{status: http.StatusBadGateway}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, requests := newFixtureServer(t, map[string]fixture{"/apis": tt.upstream})
+			probe := newUpstreamProbe()
+
+			rec := httptest.NewRecorder()
+			handleHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("/healthz status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if len(*requests) != 0 {
+				t.Errorf("/healthz made %d upstream requests, want 0", len(*requests))
+			}
+
+			rec = httptest.NewRecorder()
+			probe.handleStatus(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rec.Code != tt.wantReadiness {
+				t.Errorf("/readyz status = %d, want %d", rec.Code, tt.wantReadiness)
+			}
+			var result ProbeResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("decoding /readyz body %q: %v", rec.Body, err)
+			}
+			if wantOK := tt.wantReadiness == http.StatusOK; (result.Status == "ok") != wantOK {
+				t.Errorf("/readyz status field = %q (reason %q)", result.Status, result.Reason)
+			}
+			if len(*requests) != 1 {
+				t.Errorf("/readyz made %d upstream requests, want 1", len(*requests))
+			}
+		})
+	}
+}
+
+func TestUpstreamProbeCheck(t *testing.T) {
+	t.Run("server error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusInternalServerError},
+		})
+
+		result, err := newUpstreamProbe().check(context.Background())
+		if err == nil {
+			t.Fatal("expected an error for a 500 response")
+		}
+		if result.Status != "unavailable" || !strings.Contains(result.Reason, "500") {
+			t.Errorf("result = %+v, want unavailable because of the 500", result)
+		}
+	})
+
+	t.Run("client error counts as reachable", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{})
+
+		result, err := newUpstreamProbe().check(context.Background())
+		if err != nil || result.Status != "ok" {
+			t.Errorf("check = %+v, %v; want ok for a 404", result, err)
+		}
+	})
+
+	t.Run("closed listener", func(t *testing.T) {
+		srv, _ := newFixtureServer(t, nil)
+		srv.Close()
+
+		result, err := newUpstreamProbe().check(context.Background())
+		if err == nil {
+			t.Fatal("expected an error when the upstream isn't listening")
+		}
+		if result.Status != "unavailable" || result.Reason == "" {
+			t.Errorf("result = %+v, want unavailable with a reason", result)
+		}
+		if errorCategory(err) != "network" {
+			t.Errorf("error category = %q, want network", errorCategory(err))
+		}
+	})
+
+	t.Run("cached within TTL", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{"/apis": {}})
+		probe := newUpstreamProbe()
+		probe.cacheTTL = time.Hour
+
+		first, _ := probe.check(context.Background())
+		second, _ := probe.check(context.Background())
+		if len(*requests) != 1 {
+			t.Errorf("got %d upstream requests, want 1", len(*requests))
+		}
+		if !second.CheckedAt.Equal(first.CheckedAt) {
+			t.Errorf("second check at %v, want the cached result of %v", second.CheckedAt, first.CheckedAt)
+		}
+	})
+
+	t.Run("probed again after TTL", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{"/apis": {}})
+		probe := newUpstreamProbe()
+		probe.cacheTTL = 0
+
+		probe.check(context.Background())
+		probe.check(context.Background())
+		if len(*requests) != 2 {
+			t.Errorf("got %d upstream requests, want 2", len(*requests))
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Fields of an API record that may hold an external, stable identifier.
+var identifierFields = []string{"identifier", "uuid", "register_number", "api_id"}
+
+var (
+	uuidRegexp           = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	registerNumberRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+)
+
+// GetAPIByIdentifierParams represents the parameters for the getAPIByIdentifier tool.
+// The `identifier` parameter is required.
+type GetAPIByIdentifierParams struct {
+	Identifier string `json:"identifier" jsonschema:"required" jsonschema_description:"Government identifier of the API: a UUID or register number."`
+}
+
+// createGetAPIByIdentifierTool creates a tool for resolving an API by its
+// external identifier.
+func createGetAPIByIdentifierTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPIByIdentifierParams]{
+		Name: "get_api_by_identifier",
+		Description: "Get an API by its government identifier (a UUID or register number), as opposed to " +
+			"its ID in the Developer Overheid API. Searches the catalog; the number of pages scanned is bounded.",
+		HandleFunc: func(ctx context.Context, params GetAPIByIdentifierParams) *mcp.CallToolResult {
+			identifier := strings.TrimSpace(params.Identifier)
+			if !uuidRegexp.MatchString(identifier) && !registerNumberRegexp.MatchString(identifier) {
+				return newToolCallErrorResult("Invalid identifier %q: must be a UUID or a register number "+
+					"(letters, digits, '.', '_' or '-')", params.Identifier)
+			}
+
+			var (
+				matches []json.RawMessage
+				ids     []string
+			)
+			_, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+				for _, field := range identifierFields {
+					if v := recordString(rec, field); v != "" && strings.EqualFold(v, identifier) {
+						matches = append(matches, item)
+						ids = append(ids, apiID(rec))
+						break
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", err)
+			}
+
+			switch {
+			case len(matches) == 0 && more:
+				return newToolCallErrorResult("No API found with identifier %v in the first %d pages of the catalog", identifier, maxPages)
+			case len(matches) == 0:
+				return newToolCallErrorResult("No API found with identifier %v", identifier)
+			case len(matches) > 1:
+				return newToolCallErrorResult("Identifier %v is ambiguous, it matches APIs with IDs: %v", identifier, strings.Join(ids, ", "))
+			}
+
+			api, err := normalizeRecords(matches[0])
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
+			}
+
+			result, err := marshalResult(api, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGetAPIByIdentifier(t *testing.T) {
+	pages := map[string]fixture{
+		"1": listPageFixture("/apis", `[
+			{"id":"a","identifier":"0F9C2A34-5B6D-4E7F-8A9B-0C1D2E3F4A5B","title":"A"},
+			{"id":"b","register_number":"REG-001","title":"B"}
+		]`, 2, 0, 2, 0),
+		"2": listPageFixture("/apis", `[
+			{"id":"c","register_number":"REG-002","title":"C"},
+			{"id":"d","uuid":"REG-002","title":"D"}
+		]`, 0, 1, 2, 0),
+	}
+	serveCatalog := func(t *testing.T) {
+		newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pages[r.URL.Query().Get("page")].serve(w)
+		}))
+	}
+
+	t.Run("exact match", func(t *testing.T) {
+		tests := []struct {
+			identifier string
+			wantID     string
+		}{
+			{"0f9c2a34-5b6d-4e7f-8a9b-0c1d2e3f4a5b", "a"},
+			{" REG-001 ", "b"},
+		}
+		for _, tt := range tests {
+			serveCatalog(t)
+
+			res := callTool(t, createGetAPIByIdentifierTool(), `{"identifier":"`+tt.identifier+`"}`)
+
+			var got struct {
+				ID string `json:"id"`
+			}
+			decodeResult(t, res, &got)
+			if got.ID != tt.wantID {
+				t.Errorf("identifier %q resolved to %q, want %q", tt.identifier, got.ID, tt.wantID)
+			}
+		}
+	})
+
+	t.Run("multiple matches", func(t *testing.T) {
+		serveCatalog(t)
+
+		res := callTool(t, createGetAPIByIdentifierTool(), `{"identifier":"REG-002"}`)
+		expectError(t, res, "ambiguous, it matches APIs with IDs: c, d")
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		serveCatalog(t)
+
+		res := callTool(t, createGetAPIByIdentifierTool(), `{"identifier":"REG-999"}`)
+		expectError(t, res, "No API found with identifier REG-999")
+	})
+
+	t.Run("no match within page budget", func(t *testing.T) {
+		serveCatalog(t)
+		maxPages = 1
+
+		res := callTool(t, createGetAPIByIdentifierTool(), `{"identifier":"REG-002"}`)
+		expectError(t, res, "in the first 1 pages")
+	})
+
+	t.Run("invalid identifier", func(t *testing.T) {
+		serveCatalog(t)
+
+		res := callTool(t, createGetAPIByIdentifierTool(), `{"identifier":"../apis"}`)
+		expectError(t, res, "Invalid identifier")
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Fields of an API record that may hold a numeric ID, besides `id`.
+var numericIDFields = []string{"number", "numeric_id", "legacy_id"}
+
+// Fields of an API record that may hold a slug, besides `id`.
+var slugIDFields = []string{"slug", "legacy_id"}
+
+var (
+	numericIDRegexp = regexp.MustCompile(`^[0-9]+$`)
+	nonSlugRegexp   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// resolveAlternateID searches the catalog for an API that has id in the other
+// ID scheme than the one it's addressed by upstream: a numeric ID is looked
+// up in the numeric ID fields, any other ID is looked up as a slug (also
+// matching slugified service names). It returns the upstream ID of the single
+// matching API, or an empty string if there is no unambiguous match.
+func resolveAlternateID(ctx context.Context, id string) (string, error) {
+	fields := slugIDFields
+	if numericIDRegexp.MatchString(id) {
+		fields = numericIDFields
+	}
+
+	var ids []string
+	_, _, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+		var rec map[string]any
+		if err := json.Unmarshal(item, &rec); err != nil {
+			return err
+		}
+
+		match := false
+		for _, field := range fields {
+			if v := recordString(rec, field); v != "" && strings.EqualFold(v, id) {
+				match = true
+				break
+			}
+		}
+		if !match && !numericIDRegexp.MatchString(id) {
+			match = slugify(apiName(rec)) == strings.ToLower(id)
+		}
+
+		if match {
+			if apiID := apiID(rec); apiID != "" && apiID != id {
+				ids = append(ids, apiID)
+			}
+		}
+		if len(ids) > 1 {
+			return errStopWalk
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if len(ids) != 1 {
+		return "", nil
+	}
+	return ids[0], nil
+}
+
+// slugify converts a name into a lowercase slug, with runs of characters other
+// than letters and digits replaced by a hyphen.
+func slugify(name string) string {
+	return strings.Trim(nonSlugRegexp.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestResolveAlternateID(t *testing.T) {
+	pages := map[string]fixture{
+		"1": listPageFixture("/apis", `[
+			{"id":"uuid-1","number":42,"slug":"bag-api","title":"Basisregistratie Adressen"},
+			{"id":"uuid-2","legacy_id":"brp","service_name":"Basisregistratie Personen"},
+			{"id":"uuid-3","title":"Open Data"},
+			{"id":"uuid-4","title":"Open data"}
+		]`, 2, 0, 2, 0),
+		"2": listPageFixture("/apis", `[{"id":"uuid-5","number":"7"},{"id":"uuid-6","slug":"2024"}]`, 0, 1, 2, 0),
+	}
+
+	tests := []struct {
+		name      string
+		id        string
+		want      string
+		wantPages []string
+	}{
+		{"numeric", "42", "uuid-1", []string{"1", "2"}},
+		{"numeric on a later page", "7", "uuid-5", []string{"1", "2"}},
+		{"numeric ID isn't looked up in slugs", "2024", "", []string{"1", "2"}},
+		{"slug", "BAG-API", "uuid-1", []string{"1", "2"}},
+		{"slug in legacy ID", "brp", "uuid-2", []string{"1", "2"}},
+		{"slugified name", "basisregistratie-personen", "uuid-2", []string{"1", "2"}},
+		{"no match", "unknown", "", []string{"1", "2"}},
+		// The second match stops the walk before page 2.
+		{"ambiguous", "open-data", "", []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requested []string
+			newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page := r.URL.Query().Get("page")
+				requested = append(requested, page)
+				pages[page].serve(w)
+			}))
+
+			got, err := resolveAlternateID(context.Background(), tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveAlternateID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+			if !slices.Equal(requested, tt.wantPages) {
+				t.Errorf("requested pages %v, want %v", requested, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := map[string]string{
+		"Basisregistratie Personen": "basisregistratie-personen",
+		"  API (v2) -- beta ":       "api-v2-beta",
+		"BRP":                       "brp",
+		"":                          "",
+	}
+	for name, want := range tests {
+		if got := slugify(name); got != want {
+			t.Errorf("slugify(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
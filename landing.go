@@ -0,0 +1,70 @@
+package main
+
+import (
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+var landingPageTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>MCP server for the Developer Overheid API Register</title>
+</head>
+<body>
+<h1>MCP server for the Developer Overheid API Register</h1>
+<p>
+This is a <a href="https://modelcontextprotocol.io/">Model Context Protocol</a> (MCP) server
+for the <a href="https://apis.developer.overheid.nl/">Developer Overheid API Register</a>.
+It is meant to be used by an MCP client, not a web browser.
+</p>
+{{- if .SSEURL}}
+<h2>SSE endpoint</h2>
+<p>Configure your MCP client to connect to: <code>{{.SSEURL}}</code></p>
+{{- end}}
+<h2>Available tools</h2>
+<ul>
+{{- range .Tools}}
+<li><code>{{.}}</code></li>
+{{- end}}
+</ul>
+<p><a href="https://github.com/dstotijn/mcp-developer-overheid-api-register">Source code</a></p>
+</body>
+</html>
+`))
+
+// landingPageData holds the values the landing page is rendered with.
+type landingPageData struct {
+	SSEURL string
+	Tools  []string
+}
+
+// withLandingPage serves an informational HTML page for browser requests to
+// "/". All other requests, including MCP (SSE) requests, are passed to next.
+func withLandingPage(next http.Handler, data landingPageData) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isLandingPageRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := landingPageTemplate.Execute(w, data); err != nil {
+			slog.ErrorContext(r.Context(), "Failed to render landing page", "error", err)
+		}
+	})
+}
+
+// isLandingPageRequest reports whether r is a browser navigation to "/". SSE
+// clients request `text/event-stream` and never ask for HTML, so this doesn't
+// shadow the MCP routes.
+func isLandingPageRequest(r *http.Request) bool {
+	if r.Method != http.MethodGet || r.URL.Path != "/" || r.URL.RawQuery != "" {
+		return false
+	}
+
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/html") && !strings.Contains(accept, "text/event-stream")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Default maximum size of an upstream response body.
+const defaultMaxResponseBytes = 10 << 20
+
+// errResponseTooLarge is returned when reading a response body beyond the
+// configured limit.
+var errResponseTooLarge = errors.New("response body exceeds the limit")
+
+// limitTransport is an http.RoundTripper that caps the size of response
+// bodies, so a misbehaving upstream can't exhaust memory. Reading beyond the
+// limit fails with an error stating the limit.
+type limitTransport struct {
+	next  http.RoundTripper
+	limit int64
+}
+
+func (t limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{body: resp.Body, remaining: t.limit, limit: t.limit}
+	return resp, nil
+}
+
+// limitedBody is a response body that fails once more than limit bytes are
+// read from it.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, b.errTooLarge()
+	}
+	// Read one byte beyond the limit, to detect bodies exceeding it.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), b.errTooLarge()
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+func (b *limitedBody) errTooLarge() error {
+	return fmt.Errorf("%w of %d bytes (see -max-response-bytes)", errResponseTooLarge, b.limit)
+}
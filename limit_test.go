@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newStreamingServer starts a server that streams a body of n bytes in small
+// chunks, without a Content-Length.
+func newStreamingServer(t *testing.T, n int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := []byte(strings.Repeat("x", 100))
+		for written := 0; written < n; written += len(chunk) {
+			if _, err := w.Write(chunk[:min(len(chunk), n-written)]); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestLimitTransport(t *testing.T) {
+	const limit = 1000
+
+	tests := []struct {
+		name    string
+		size    int
+		wantErr bool
+	}{
+		{"under the limit", limit - 1, false},
+		{"at the limit", limit, false},
+		{"one byte over", limit + 1, true},
+		{"streamed far past the limit", 100 * limit, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newStreamingServer(t, tt.size)
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := limitTransport{next: http.DefaultTransport, limit: limit}.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if !tt.wantErr {
+				if err != nil || len(body) != tt.size {
+					t.Errorf("read %d bytes, error %v; want all %d bytes", len(body), err, tt.size)
+				}
+				return
+			}
+			if !errors.Is(err, errResponseTooLarge) {
+				t.Fatalf("got error %v, want errResponseTooLarge", err)
+			}
+			if !strings.Contains(err.Error(), "1000 bytes") {
+				t.Errorf("error %q doesn't state the limit", err)
+			}
+			if len(body) != limit {
+				t.Errorf("read %d bytes before failing, want %d", len(body), limit)
+			}
+		})
+	}
+
+	t.Run("tool result", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {body: `{"id":"a","description":"` + strings.Repeat("x", 2*limit) + `"}`},
+		})
+		httpClient.Transport = limitTransport{next: httpClient.Transport, limit: limit}
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		expectError(t, res, "exceeds the limit of 1000 bytes")
+	})
+}
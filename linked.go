@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Fields of a repository record that may hold the APIs it's linked to.
+var linkedAPIFields = []string{"apis", "api_ids", "linked_apis"}
+
+// ListRepositoryAPIsParams represents the parameters for the listRepositoryAPIs tool.
+// The `repositoryId` parameter is required.
+type ListRepositoryAPIsParams struct {
+	RepositoryID string `json:"repositoryId" jsonschema:"required" jsonschema_description:"ID of the repository."`
+}
+
+// ListRepositoryAPIsResponse represents the response from the listRepositoryAPIs tool.
+type ListRepositoryAPIsResponse struct {
+	RepositoryID string            `json:"repository_id"`
+	APIs         []json.RawMessage `json:"apis"`
+	Count        int               `json:"count"`
+	Errors       map[string]string `json:"errors,omitempty"`
+}
+
+// createListRepositoryAPIsTool creates a tool for listing the APIs linked to a
+// repository.
+func createListRepositoryAPIsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListRepositoryAPIsParams]{
+		Name: "list_repository_apis",
+		Description: "List the APIs linked to a repository by repository ID. Each linked API is fetched in full, in the " +
+			"order the repository lists them; APIs that can't be fetched are reported in `errors`, keyed by API ID.",
+		HandleFunc: func(ctx context.Context, params ListRepositoryAPIsParams) *mcp.CallToolResult {
+			repo, err := fetchRepository(ctx, params.RepositoryID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching repository: %v", err)
+			}
+
+			response := ListRepositoryAPIsResponse{
+				RepositoryID: apiID(repo),
+				APIs:         []json.RawMessage{},
+			}
+			if response.RepositoryID == "" {
+				response.RepositoryID = params.RepositoryID
+			}
+
+			ids := linkedAPIIDs(repo)
+			apis, errs := fetchConcurrent(ctx, ids, func(ctx context.Context, id string) (json.RawMessage, error) {
+				api, err := fetchAPIRaw(ctx, id)
+				if err != nil {
+					return nil, err
+				}
+				return normalizeRecords(api)
+			})
+			if ctx.Err() != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", ctx.Err())
+			}
+
+			for i, id := range ids {
+				if errs[i] != nil {
+					if response.Errors == nil {
+						response.Errors = make(map[string]string)
+					}
+					response.Errors[id] = errs[i].Error()
+					continue
+				}
+				response.APIs = append(response.APIs, apis[i])
+			}
+			response.Count = len(response.APIs)
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// linkedAPIIDs returns the unique IDs of the APIs a decoded repository record
+// links to. Links may be plain IDs (strings or numbers) or API objects with an
+// `id` field.
+func linkedAPIIDs(repo map[string]any) []string {
+	var ids []string
+	for _, field := range linkedAPIFields {
+		links, _ := repo[field].([]any)
+		for _, link := range links {
+			var id string
+			switch v := link.(type) {
+			case string:
+				id = v
+			case float64:
+				id = fmt.Sprintf("%v", v)
+			case map[string]any:
+				id = apiID(v)
+			}
+			if id != "" && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
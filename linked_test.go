@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestListRepositoryAPIs(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/repositories/r1": {body: `{"id":"r1","apis":[{"id":"a","title":"A"},"b"],"api_ids":["a"]}`},
+		"/repositories/r2": {body: `{"id":"r2","apis":["a","gone"]}`},
+		"/repositories/r3": {body: `{"id":"r3"}`},
+		"/apis/a":          {body: `{"id":"a","title":"API a"}`},
+		"/apis/b":          {body: `{"id":"b","title":"API b"}`},
+	})
+
+	decode := func(t *testing.T, args string) (ListRepositoryAPIsResponse, []string) {
+		t.Helper()
+
+		var got ListRepositoryAPIsResponse
+		decodeResult(t, callTool(t, createListRepositoryAPIsTool(), args), &got)
+
+		var ids []string
+		for _, raw := range got.APIs {
+			var api struct {
+				ID    string `json:"id"`
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal(raw, &api); err != nil {
+				t.Fatal(err)
+			}
+			if api.Title != "API "+api.ID {
+				t.Errorf("API %v = %s, want the full record", api.ID, raw)
+			}
+			ids = append(ids, api.ID)
+		}
+		return got, ids
+	}
+
+	t.Run("two APIs", func(t *testing.T) {
+		got, ids := decode(t, `{"repositoryId":"r1"}`)
+		if !slices.Equal(ids, []string{"a", "b"}) || got.Count != 2 || len(got.Errors) > 0 {
+			t.Errorf("APIs = %q, count = %d, errors = %v; want a and b", ids, got.Count, got.Errors)
+		}
+		if got.RepositoryID != "r1" {
+			t.Errorf("repository ID = %q, want r1", got.RepositoryID)
+		}
+	})
+
+	t.Run("API not found", func(t *testing.T) {
+		got, ids := decode(t, `{"repositoryId":"r2"}`)
+		if !slices.Equal(ids, []string{"a"}) || got.Count != 1 {
+			t.Errorf("APIs = %q, count = %d; want only a", ids, got.Count)
+		}
+		if _, ok := got.Errors["gone"]; !ok || len(got.Errors) != 1 {
+			t.Errorf("errors = %v, want an error for gone", got.Errors)
+		}
+	})
+
+	t.Run("no APIs", func(t *testing.T) {
+		got, ids := decode(t, `{"repositoryId":"r3"}`)
+		if len(ids) != 0 || got.Count != 0 || got.APIs == nil {
+			t.Errorf("APIs = %v, count = %d; want an empty list", got.APIs, got.Count)
+		}
+	})
+
+	t.Run("repository not found", func(t *testing.T) {
+		res := callTool(t, createListRepositoryAPIsTool(), `{"repositoryId":"r4"}`)
+		expectError(t, res, "repository with ID r4 not found")
+	})
+}
+
+func TestLinkedAPIIDs(t *testing.T) {
+	var repo map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"apis": [{"id":"a"}, "b", 42, {"title":"no id"}, ""],
+		"api_ids": ["b", "c"],
+		"linked_apis": [{"id":"d"}, {"id":"a"}]
+	}`), &repo); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := linkedAPIIDs(repo), []string{"a", "b", "42", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("linkedAPIIDs() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// ListAllAPIsParams represents the parameters for the listAllAPIs tool.
+type ListAllAPIsParams struct{}
+
+// ListAllAPIsResponse represents the response from the listAllAPIs tool.
+type ListAllAPIsResponse struct {
+	APIs      []json.RawMessage `json:"apis"`
+	PageCount int               `json:"page_count"`
+	Truncated bool              `json:"truncated,omitempty"`
+	Error     string            `json:"error,omitempty"`
+}
+
+// createListAllAPIsTool creates a tool for listing all APIs, following
+// pagination.
+func createListAllAPIsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListAllAPIsParams]{
+		Name: "list_all_apis",
+		Description: "List all APIs from the Developer Overheid API in a single call, following pagination. " +
+			"`page_count` is the number of pages aggregated; the number of pages is bounded, and `truncated` " +
+			"is set when more pages were available.",
+		HandleFunc: func(ctx context.Context, params ListAllAPIsParams) *mcp.CallToolResult {
+			response := ListAllAPIsResponse{
+				APIs: []json.RawMessage{},
+			}
+
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				item, err := normalizeRecords(item)
+				if err != nil {
+					return err
+				}
+				response.APIs = append(response.APIs, item)
+				return nil
+			})
+			if err != nil {
+				if !errors.Is(err, errPageTimeout) {
+					return newToolCallErrorResult("Error fetching APIs: %v", err)
+				}
+				response.Error = err.Error()
+				more = true
+			}
+
+			response.PageCount = pages
+			response.Truncated = more
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Log formats.
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// newLogger returns a logger writing to w with the given minimum level (debug,
+// info, warn or error) and format (text or json). Records logged within a tool
+// call carry its request ID.
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q, must be one of: debug, info, warn, error", level)
+	}
+	opts := &slog.HandlerOptions{Level: l}
+
+	switch strings.ToLower(format) {
+	case logFormatText:
+		return slog.New(requestIDHandler{slog.NewTextHandler(w, opts)}), nil
+	case logFormatJSON:
+		return slog.New(requestIDHandler{slog.NewJSONHandler(w, opts)}), nil
+	}
+
+	return nil, fmt.Errorf("invalid log format %q, must be %q or %q", format, logFormatText, logFormatJSON)
+}
+
+// fatal logs a message at error level (to stderr) and exits.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// loggingTransport is an http.RoundTripper that logs every outbound request at
+// debug level. Request headers aren't logged, as they may hold credentials
+// (see -auth-token and -header), and passwords in URLs are redacted.
+type loggingTransport struct {
+	next   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	attrs := []any{"method", req.Method, "url", req.URL.Redacted(), "latency", time.Since(start)}
+	if tool := toolFromContext(ctx); tool != "" {
+		attrs = append(attrs, "tool", tool)
+	}
+	if err != nil {
+		t.logger.DebugContext(ctx, "Upstream request failed", append(attrs, "error", err)...)
+		return nil, err
+	}
+	t.logger.DebugContext(ctx, "Upstream request", append(attrs, "status", resp.StatusCode)...)
+
+	return resp, nil
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newJSONLogger returns a debug level logger writing JSON records to the
+// returned buffer.
+func newJSONLogger(t *testing.T) (*slog.Logger, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "debug", logFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logger, &buf
+}
+
+// logRecords decodes the JSON log records in buf.
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("decoding log record: %v", err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestLoggingTransport(t *testing.T) {
+	srv, _ := newFixtureServer(t, map[string]fixture{
+		"/apis": {status: http.StatusTeapot},
+	})
+	logger, buf := newJSONLogger(t)
+	rt := loggingTransport{next: http.DefaultTransport, logger: logger}
+
+	ctx := contextWithTool(context.Background(), "list_apis")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/apis?page=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	srv.Close()
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/apis", nil)
+	if _, err := rt.RoundTrip(req); err == nil {
+		t.Fatal("expected an error from a closed server")
+	}
+
+	records := logRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d log records, want 2: %v", len(records), records)
+	}
+
+	rec := records[0]
+	want := map[string]any{
+		"level":  "DEBUG",
+		"msg":    "Upstream request",
+		"method": "GET",
+		"url":    srv.URL + "/apis?page=2",
+		"status": float64(http.StatusTeapot),
+		"tool":   "list_apis",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%v = %v, want %v", k, rec[k], v)
+		}
+	}
+	if _, ok := rec["latency"].(float64); !ok {
+		t.Errorf("latency = %v, want a duration", rec["latency"])
+	}
+
+	rec = records[1]
+	if rec["msg"] != "Upstream request failed" {
+		t.Errorf("msg = %v, want the failure message", rec["msg"])
+	}
+	if errText, _ := rec["error"].(string); !strings.Contains(errText, "connect") {
+		t.Errorf("error = %q, want the connection error", errText)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	if _, err := newLogger(&bytes.Buffer{}, "verbose", logFormatText); err == nil {
+		t.Error("expected an error for an invalid level")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("expected an error for an invalid format")
+	}
+
+	// Records below the level are dropped; the format is case-insensitive.
+	var buf bytes.Buffer
+	logger, err := newLogger(&buf, "warn", "JSON")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("dropped")
+	logger.Warn("kept")
+	if records := logRecords(t, &buf); len(records) != 1 || records[0]["msg"] != "kept" {
+		t.Errorf("records = %v, want only the warning", records)
+	}
+}
+
+func TestLoggingTransportRedactsCredentials(t *testing.T) {
+	const token = "s3cret-token"
+
+	var gotAuth string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	logger, buf := newJSONLogger(t)
+
+	// Like in main, the credentials are added below the logging transport.
+	u, _ := url.Parse(srv.URL)
+	rt := loggingTransport{
+		next:   headerTransport{next: http.DefaultTransport, host: u.Host, header: upstreamHeaders(nil, token)},
+		logger: logger,
+	}
+
+	u.User = url.UserPassword("user", "hunter2")
+	req, err := http.NewRequest(http.MethodGet, u.String()+"/apis", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if gotAuth != "Bearer "+token {
+		t.Fatalf("upstream got Authorization %q, want the bearer token", gotAuth)
+	}
+	out := buf.String()
+	for _, secret := range []string{token, "hunter2"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output %q contains %q", out, secret)
+		}
+	}
+	records := logRecords(t, buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d log records, want 1", len(records))
+	}
+	if logged, want := records[0]["url"], "http://user:xxxxx@"+u.Host+"/apis"; logged != want {
+		t.Errorf("url = %v, want %v", logged, want)
+	}
+}
+
+func TestToolCallsDontWriteToStdout(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis": {body: `[{"id":"a"}]`},
+	})
+
+	// pipe replaces *f with a pipe for the duration of the test, and returns
+	// a function that restores it and returns everything written to it.
+	pipe := func(f **os.File) func() []byte {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		old := *f
+		*f = w
+		t.Cleanup(func() { *f = old })
+		done := make(chan []byte)
+		go func() {
+			data, _ := io.ReadAll(r)
+			done <- data
+		}()
+		return func() []byte {
+			*f = old
+			w.Close()
+			return <-done
+		}
+	}
+	stdout, stderr := pipe(&os.Stdout), pipe(&os.Stderr)
+
+	// Set up logging like main does, at the most verbose level.
+	logger, err := newLogger(os.Stderr, "debug", logFormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(oldLogger) })
+	slog.SetDefault(logger)
+	httpClient = newUpstreamClient(http.DefaultTransport.(*http.Transport).Clone(), logger)
+
+	callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+	callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+	callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":" "}`)
+	log.Print("standard library log output")
+
+	out, errOut := stdout(), stderr()
+	if len(out) != 0 {
+		t.Errorf("tool calls wrote %d bytes to stdout: %q", len(out), out)
+	}
+	if !bytes.Contains(errOut, []byte("Upstream request")) || !bytes.Contains(errOut, []byte("standard library log output")) {
+		t.Errorf("stderr = %q, want the log output", errOut)
+	}
+}
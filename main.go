@@ -3,17 +3,20 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"mime"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/signal"
-	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -22,121 +25,372 @@ import (
 	"github.com/dstotijn/go-mcp"
 )
 
-// Base URL for the Developer Overheid API.
-const apiBaseURL = "https://apis.developer.overheid.nl/api/v0"
+// Default base URL for the Developer Overheid API.
+const defaultAPIBaseURL = "https://apis.developer.overheid.nl/api/v0"
+
+// Maximum page size that can be requested from list endpoints.
+const maxPerPage = 100
+
+// Languages in which API records can be requested. The first is the default.
+var supportedLanguages = []string{"nl", "en"}
 
 // ListAPIsParams represents the parameters for the listAPIs tool.
-// The `page` parameter is optional.
+// The `page` parameter is optional; pages are numbered from 1, and an omitted
+// page (or page 0) means the first page. The `perPage`, `organization` and
+// `category` parameters are optional.
 type ListAPIsParams struct {
-	Page int `json:"page,omitempty"`
+	Page         *int   `json:"page,omitempty" jsonschema_description:"Page number, starting at 1. Defaults to 1 (also for 0)."`
+	PerPage      int    `json:"perPage,omitempty" jsonschema_description:"Number of APIs per page, at most 100. Defaults to the upstream page size."`
+	Organization string `json:"organization,omitempty" jsonschema_description:"Only return APIs of the organization with this name (case-insensitive)."`
+	Category     string `json:"category,omitempty" jsonschema_description:"Only return APIs in this category (see list_categories)."`
 }
 
 // ListAPIsResponse represents the response from the listAPIs tool.
+// Page numbers are taken from the Link header, the total count of APIs from
+// the X-Total-Count header; each is omitted when upstream doesn't report it.
 type ListAPIsResponse struct {
-	APIs     json.RawMessage `json:"apis"`
-	NextPage int             `json:"next_page,omitempty"`
+	APIs       json.RawMessage `json:"apis"`
+	NextPage   int             `json:"next_page,omitempty"`
+	PrevPage   int             `json:"prev_page,omitempty"`
+	LastPage   int             `json:"last_page,omitempty"`
+	TotalCount int             `json:"total_count,omitempty"`
 }
 
 // GetAPIParams represents the parameters for the getAPI tool.
-// The `id` parameter is required.
+// The `id` parameter is required. The `lang` parameter is optional and
+// defaults to "nl".
 type GetAPIParams struct {
-	ID string `json:"id"`
+	ID   string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+	Lang string `json:"lang,omitempty" jsonschema_description:"Language of the response: nl or en. Defaults to nl."`
 }
 
 // ListRepositoriesParams represents the parameters for the listRepositories tool.
-// The `page` parameter is optional.
+// The `page` parameter is optional; pages are numbered from 1, and an omitted
+// page (or page 0) means the first page. The `perPage` parameter is optional.
 type ListRepositoriesParams struct {
-	Page int `json:"page,omitempty"`
+	Page    *int `json:"page,omitempty" jsonschema_description:"Page number, starting at 1. Defaults to 1 (also for 0)."`
+	PerPage int  `json:"perPage,omitempty" jsonschema_description:"Number of repositories per page, at most 100. Defaults to the upstream page size."`
 }
 
 // ListRepositoriesResponse represents the response from the listRepositories tool.
+// Page numbers are taken from the Link header, the total count of repositories
+// from the X-Total-Count header; each is omitted when upstream doesn't report it.
 type ListRepositoriesResponse struct {
 	Repositories json.RawMessage `json:"repositories"`
 	NextPage     int             `json:"next_page,omitempty"`
+	PrevPage     int             `json:"prev_page,omitempty"`
+	LastPage     int             `json:"last_page,omitempty"`
+	TotalCount   int             `json:"total_count,omitempty"`
 }
 
-// Command-line flags.
-var (
-	httpAddr string
-	useStdio bool
-	useSSE   bool
+// Default timeout for requests to upstream servers.
+const defaultHTTPTimeout = 30 * time.Second
+
+// Connection pool of the upstream transport. Nearly all requests go to the
+// single API host, so the idle connections per host aren't capped below the
+// overall limit (net/http defaults to 2 per host), letting traversals and
+// concurrent fetches reuse connections.
+const (
+	defaultMaxIdleConns = 16
+	idleConnTimeout     = 90 * time.Second
 )
 
+// Timeouts of the HTTP server. Reading request headers is bounded to mitigate
+// slow-loris attacks, and idle keep-alive connections are closed eventually.
+// There's deliberately no read or write timeout: both would cut off long-lived
+// SSE streams, whose requests never finish while the client is connected.
+const (
+	readHeaderTimeout = 10 * time.Second
+	idleTimeout       = 2 * time.Minute
+)
+
+// HTTP client for requests to upstream servers, configured in main.
+var httpClient = http.DefaultClient
+
+// Command-line flags.
 var (
-	hateoasLinkRegexp = regexp.MustCompile(`<([^>]+)>`)
-	relRegexp         = regexp.MustCompile(`rel="([^"]+)"`)
+	apiBaseURL string
+	httpAddr   string
+	useStdio   bool
+	useSSE     bool
+	publicURL  string
+
+	lenientErrors      bool
+	idFallback         bool
+	collapseWhitespace bool
+	compactRecords     bool
+	structuredOutput   bool
+	prettyOutput       bool
+	outputFormat       string
+	prettyErrors       bool
+	maxOutputChars     int
+	maxPages           int
+	linkFallback       bool
+	fetchConcurrency   int
+	pageTimeout        time.Duration
+	maxRetries         int
+	httpTimeout        time.Duration
+	maxResponseBytes   int64
+	maxIdleConns       int
+	proxyURL           string
+	allowedHosts       string
+	caCertFile         string
+	insecureSkipVerify bool
+	requestHeaders     headerFlag
+	authToken          string
+	requestTimeout     time.Duration
+	refreshInterval    time.Duration
+	cacheTTL           time.Duration
+	rateLimit          float64
+	rateBurst          int
+	logLevel           string
+	logFormat          string
+	dryRun             bool
+	includeHeaders     bool
+	includedHeaders    headerNamesFlag
+	enabledTools       string
+	toolPrefix         string
+	showVersion        bool
 )
 
+// serverTool maps the name of a tool to its constructor.
+type serverTool struct {
+	name   string
+	create func() mcp.Tool
+}
+
+// serverTools lists the tools provided by the server, in registration order.
+var serverTools = []serverTool{
+	{"list_apis", func() mcp.Tool { return createListAPIsTool(httpClient, apiBaseURL) }},
+	{"list_apis_summary", func() mcp.Tool { return createListAPIsSummaryTool(httpClient, apiBaseURL) }},
+	{"list_all_apis", createListAllAPIsTool},
+	{"list_apis_range", createListAPIsRangeTool},
+	{"stream_catalog", createStreamCatalogTool},
+	{"search_apis", func() mcp.Tool { return createSearchAPIsTool(httpClient, apiBaseURL) }},
+	{"get_api", func() mcp.Tool { return createGetAPITool(httpClient, apiBaseURL) }},
+	{"get_apis_batch", createGetAPIsBatchTool},
+	{"diff_apis", createDiffAPIsTool},
+	{"list_repositories", func() mcp.Tool { return createListRepositoriesTool(httpClient, apiBaseURL) }},
+	{"get_repository", createGetRepositoryTool},
+	{"find_repository", createFindRepositoryTool},
+	{"list_repository_apis", createListRepositoryAPIsTool},
+	{"list_organizations", createListOrganizationsTool},
+	{"get_organization", createGetOrganizationTool},
+	{"get_api_specification", createGetAPISpecificationTool},
+	{"oas_operations_summary", createOASOperationsSummaryTool},
+	{"validate_oas_url", createValidateOASURLTool},
+	{"validate_api_specification", createValidateAPISpecificationTool},
+	{"advanced_list_apis", createAdvancedListAPIsTool},
+	{"validate_api_contact", createValidateAPIContactTool},
+	{"generate_snippet", createGenerateSnippetTool},
+	{"list_api_types", createListAPITypesTool},
+	{"list_categories", createListCategoriesTool},
+	{"list_recent_apis", createListRecentAPIsTool},
+	{"get_api_by_identifier", createGetAPIByIdentifierTool},
+	{"catalog_diff", createCatalogDiffTool},
+	{"hash_api", createHashAPITool},
+	{"get_api_usage_policy", createGetAPIUsagePolicyTool},
+	{"get_api_dcat", createGetAPIDCATTool},
+	{"list_apis_by_security", createListAPIsBySecurityTool},
+	{"export_catalog_bundle", createExportCatalogBundleTool},
+	{"get_compact_keys", createGetCompactKeysTool},
+	{"ping_upstream", createPingUpstreamTool},
+}
+
 func main() {
+	flag.StringVar(&apiBaseURL, "api-base-url", defaultAPIBaseURL, "Base URL of the Developer Overheid API")
 	flag.StringVar(&httpAddr, "http", ":8080", "HTTP listen address for JSON-RPC over HTTP")
 	flag.BoolVar(&useStdio, "stdio", true, "Enable stdio transport")
 	flag.BoolVar(&useSSE, "sse", false, "Enable SSE transport")
-	flag.Parse()
+	flag.StringVar(&publicURL, "public-url", "", "Public base URL the SSE transport is advertised on, e.g. behind a TLS-terminating reverse proxy (default derived from -http)")
+	flag.IntVar(&maxIdleConns, "max-idle-conns", defaultMaxIdleConns, "Maximum number of idle (keep-alive) connections to upstream servers, also per host (0 disables keep-alive)")
+	flag.DurationVar(&httpTimeout, "http-timeout", defaultHTTPTimeout, "Timeout for requests to upstream servers (0 disables)")
+	flag.IntVar(&maxRetries, "max-retries", defaultMaxRetries, "Maximum number of retries for upstream requests failing with a connection error, 429 or 5xx")
+	flag.StringVar(&enabledTools, "tools", "", "Comma-separated list of tools to register, e.g. list_apis,get_api (default all)")
+	flag.StringVar(&toolPrefix, "tool-prefix", "", "Prefix for the names of all tools, e.g. doa_ to register list_apis as doa_list_apis")
+	flag.BoolVar(&includeHeaders, "include-headers", false, "Include rate limiting, caching and deprecation headers of upstream responses in a _headers field of tool results")
+	flag.Var(&includedHeaders, "include-header", "Name of an additional upstream response header to include with -include-headers (can be repeated)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Don't send upstream requests; tools return the URL they would fetch instead")
+	flag.IntVar(&fetchConcurrency, "fetch-concurrency", defaultFetchConcurrency, "Maximum number of concurrent upstream fetches per tool call, for tools fetching multiple records")
+	flag.BoolVar(&linkFallback, "link-fallback", false, "When traversing the catalog, follow a full page without a \"next\" Link relation with the next page number, until an empty page")
+	flag.IntVar(&maxPages, "max-pages", defaultMaxPages, "Maximum number of upstream pages fetched by tools that traverse the catalog")
+	flag.DurationVar(&pageTimeout, "request-timeout-per-page", defaultPageTimeout, "Timeout for fetching a single page while traversing the catalog (0 disables)")
+	flag.DurationVar(&cacheTTL, "cache-ttl", defaultCacheTTL, "Duration for which successful upstream responses are cached (0 disables)")
+	flag.StringVar(&authToken, "auth-token", "", "Bearer token to authenticate requests to the Developer Overheid API with")
+	flag.Var(&requestHeaders, "header", "Header to send with every request to the Developer Overheid API, formatted as \"Name: value\" (can be repeated)")
+	flag.StringVar(&caCertFile, "ca-cert", "", "Path to a PEM file with CA certificates to trust for TLS connections to upstream servers, in addition to the system roots")
+	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Don't verify TLS certificates of upstream servers (dangerous, for local testing only)")
+	flag.StringVar(&allowedHosts, "allowed-hosts", "", "Comma-separated list of hosts (including their subdomains) that URLs from API records and tool parameters may be fetched from (default any public host)")
+	flag.StringVar(&proxyURL, "proxy", "", "URL of an HTTP(S) or SOCKS5 proxy for requests to upstream servers (default from HTTPS_PROXY/HTTP_PROXY)")
+	flag.Int64Var(&maxResponseBytes, "max-response-bytes", defaultMaxResponseBytes, "Maximum size of an upstream response body, in bytes (0 disables)")
+	flag.IntVar(&maxOutputChars, "max-output-chars", 0, "Maximum number of characters in the result of a tool, beyond which it's truncated (0 disables)")
+	flag.BoolVar(&prettyErrors, "pretty-errors", false, "Append remediation hints to the error results of tools")
+	flag.StringVar(&outputFormat, "output-format", outputFormatJSON, "Format of the results of list tools: json, or ndjson for one record per line")
+	flag.BoolVar(&prettyOutput, "pretty", false, "Indent the JSON results of tools")
+	flag.DurationVar(&requestTimeout, "request-timeout", defaultRequestTimeout, "Timeout for a single tool call, including all of its upstream requests (0 disables)")
+	flag.Float64Var(&rateLimit, "rate-limit", defaultRateLimit, "Maximum number of outbound requests per second (0 disables)")
+	flag.IntVar(&rateBurst, "rate-burst", defaultRateBurst, "Maximum burst of outbound requests allowed by -rate-limit")
+	flag.DurationVar(&refreshInterval, "refresh-interval", 0, "Interval for refreshing the first catalog pages in the background, which enables caching of catalog pages (0 disables)")
+	flag.BoolVar(&collapseWhitespace, "collapse-whitespace", false, "Collapse runs of whitespace in string fields of returned records")
+	flag.BoolVar(&compactRecords, "compact-records", false, "Omit null and empty fields from returned records and shorten field names (see the get_compact_keys tool)")
+	flag.BoolVar(&idFallback, "id-fallback", false, "When get_api finds no API by ID, search the catalog for an API with that ID in the other ID scheme (numeric or slug)")
+	flag.BoolVar(&structuredOutput, "structured-output", false, "Return JSON tool results as embedded resources with the application/json MIME type instead of text content")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level of log messages: debug, info, warn or error")
+	flag.StringVar(&logFormat, "log-format", logFormatText, "Format of log messages: text or json")
+	flag.BoolVar(&lenientErrors, "lenient-errors", false, "Report a 404 from get_api as a regular (non-error) \"not found\" result")
+	flag.BoolVar(&showVersion, "version", false, "Print version information and exit")
+
+	args, err := expandArgFiles(os.Args[1:])
+	if err != nil {
+		fatal("Failed to read arguments file", "error", err)
+	}
+	if err := flag.CommandLine.Parse(args); err != nil {
+		fatal("Failed to parse flags", "error", err)
+	}
+	if err := applyEnvFlags(flag.CommandLine, os.LookupEnv); err != nil {
+		fatal("Failed to read flags from environment", "error", err)
+	}
+
+	if showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	// Logs must never go to stdout: with the stdio transport, stdout carries
+	// the JSON-RPC stream. Setting the default logger also routes output of
+	// the standard `log` package to this logger.
+	logger, err := newLogger(os.Stderr, logLevel, logFormat)
+	if err != nil {
+		fatal("Invalid logging configuration", "error", err)
+	}
+	slog.SetDefault(logger)
+
+	if err := validateOutputFormat(outputFormat); err != nil {
+		fatal("Invalid output format", "error", err)
+	}
+
+	apiBaseURL, err = parseBaseURL(apiBaseURL)
+	if err != nil {
+		fatal("Invalid API base URL", "error", err)
+	}
+
+	allowedHostList, err = parseAllowedHosts(allowedHosts)
+	if err != nil {
+		fatal("Invalid allowed hosts", "error", err)
+	}
+
+	proxy, err := proxyFunc(proxyURL)
+	if err != nil {
+		fatal("Invalid proxy URL", "error", err)
+	}
+	tlsClientConfig, err := tlsConfig(caCertFile, insecureSkipVerify)
+	if err != nil {
+		fatal("Invalid CA certificate file", "path", caCertFile, "error", err)
+	}
+	if insecureSkipVerify {
+		slog.Warn("TLS certificate verification of upstream servers is disabled")
+	}
+	httpClient = newUpstreamClient(newBaseTransport(proxy, tlsClientConfig), logger)
+
+	if err := validateListenAddr(httpAddr); err != nil {
+		fatal("Invalid listen address", "error", err)
+	}
+	publicBaseURL, err := parsePublicURL(publicURL)
+	if err != nil {
+		fatal("Invalid public URL", "error", err)
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
-	transports := []string{}
+	transports, err := resolveTransports(useStdio, useSSE)
+	if err != nil {
+		fatal("Invalid configuration", "error", err)
+	}
+
+	tools, err := selectTools(serverTools, enabledTools)
+	if err != nil {
+		fatal("Invalid tools", "error", err)
+	}
+	if err := validateToolPrefix(toolPrefix); err != nil {
+		fatal("Invalid tool prefix", "error", err)
+	}
+
 	opts := []mcp.ServerOption{}
 
 	if useStdio {
-		transports = append(transports, "stdio")
 		opts = append(opts, mcp.WithStdioTransport())
+
+		if isTerminal(os.Stdin) {
+			slog.Warn("Stdin is an interactive terminal, but the stdio transport expects an MCP client " +
+				"to send JSON-RPC messages. To run the server manually, use `--stdio=false --sse` instead.")
+		}
 	}
 
-	var sseURL url.URL
+	var (
+		sseURL   url.URL
+		listener net.Listener
+	)
 
 	if useSSE {
-		transports = append(transports, "sse")
-
-		host := "localhost"
-
-		hostPart, port, err := net.SplitHostPort(httpAddr)
+		// Listen before building the SSE URL, so that the actual port is
+		// advertised when binding to port 0.
+		listener, err = net.Listen("tcp", httpAddr)
 		if err != nil {
-			log.Fatalf("Failed to split host and port: %v", err)
+			fatal("Failed to listen", "addr", httpAddr, "error", err)
 		}
 
-		if hostPart != "" {
-			host = hostPart
-		}
-
-		sseURL = url.URL{
-			Scheme: "http",
-			Host:   host + ":" + port,
-		}
+		sseURL = advertisedURL(publicBaseURL, httpAddr, listener.Addr())
 
 		opts = append(opts, mcp.WithSSETransport(sseURL))
 	}
 
+	if refreshInterval > 0 {
+		// Entries outlive a single failed refresh, but never serve data
+		// older than two intervals.
+		catalogPages = newPageCache(2 * refreshInterval)
+		go runRefresher(ctx, catalogPages, refreshInterval)
+	}
+
 	mcpServer := mcp.NewServer(mcp.ServerConfig{}, opts...)
 
-	mcpServer.Start(ctx)
+	// The MCP server outlives the signal context, so that in-flight tool
+	// calls can finish during shutdown.
+	serverCtx, stopServer := context.WithCancel(context.Background())
+	defer stopServer()
 
-	mcpServer.RegisterTools(
-		createListAPIsTool(),
-		createGetAPITool(),
-		createListRepositoriesTool(),
-	)
+	mcpServer.Start(serverCtx)
 
-	httpServer := &http.Server{
-		Addr:    httpAddr,
-		Handler: mcpServer,
-		BaseContext: func(l net.Listener) context.Context {
-			return ctx
-		},
+	toolNames := make([]string, 0, len(tools))
+	for _, t := range tools {
+		mcpServer.RegisterTools(t.create())
+		toolNames = append(toolNames, toolPrefix+t.name)
 	}
 
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /catalog/stream", handleCatalogStream)
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", upstream.handleStatus)
+	mux.Handle("GET /metrics", metricsHandler())
+	mux.Handle("/", withLandingPage(mcpServer, landingPageData{
+		SSEURL: sseURL.String(),
+		Tools:  toolNames,
+	}))
+
+	httpServer := newHTTPServer(ctx, httpAddr, mux)
+
 	if useSSE {
 		go func() {
-			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				log.Fatalf("HTTP server error: %v", err)
+			if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+				fatal("HTTP server error", "error", err)
 			}
 		}()
 	}
 
-	log.Printf("MCP server started, using transports: %v", transports)
+	v, _, _ := buildInfo()
+	slog.Info("MCP server started", "version", v, "transports", transports)
 	if useSSE {
-		log.Printf("SSE transport endpoint: %v", sseURL.String())
+		slog.Info("SSE transport enabled", "endpoint", sseURL.String())
 	}
 
 	// Wait for interrupt signal.
@@ -148,16 +402,36 @@ func main() {
 	cancelContext, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	log.Printf("Shutting down server (waiting %s). Press Ctrl+C to force quit.", timeout)
+	slog.Info("Shutting down server. Press Ctrl+C to force quit.", "timeout", timeout)
+
+	var sseServer *http.Server
+	if useSSE {
+		sseServer = httpServer
+	}
+	shutdown(cancelContext, &inflightCalls, stopServer, sseServer)
+}
 
+// shutdown stops accepting tool calls (e.g. over stdio) and waits for the
+// in-flight ones before calling stopServer, while concurrently shutting down
+// httpServer, if not nil. It returns once both are done, or ctx is done.
+func shutdown(ctx context.Context, calls *callTracker, stopServer context.CancelFunc, httpServer *http.Server) {
 	var wg sync.WaitGroup
 
-	if useSSE {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer stopServer()
+		if err := calls.drain(ctx); err != nil {
+			slog.Warn("Tool calls still in flight at shutdown", "error", err)
+		}
+	}()
+
+	if httpServer != nil {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			if err := httpServer.Shutdown(cancelContext); err != nil && !errors.Is(err, context.DeadlineExceeded) {
-				log.Printf("HTTP server shutdown error: %v", err)
+			if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+				slog.Error("HTTP server shutdown error", "error", err)
 			}
 		}()
 	}
@@ -165,91 +439,322 @@ func main() {
 	wg.Wait()
 }
 
-func createListAPIsTool() mcp.Tool {
-	return mcp.CreateTool(mcp.ToolDef[ListAPIsParams]{
-		Name:        "list_apis",
-		Description: "List all APIs from the Developer Overheid API.",
-		HandleFunc: func(ctx context.Context, params ListAPIsParams) *mcp.CallToolResult {
-			page := params.Page
-			if page == 0 {
-				page = 1
-			}
+// newHTTPServer returns the HTTP server for handler, with the timeouts
+// described at readHeaderTimeout. Requests are served with a context derived
+// from ctx.
+func newHTTPServer(ctx context.Context, addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		IdleTimeout:       idleTimeout,
+		BaseContext: func(l net.Listener) context.Context {
+			return ctx
+		},
+	}
+}
+
+// newBaseTransport returns the transport for upstream connections, through
+// proxy (if not nil) and with tlsClientConfig. Idle connections are kept for
+// reuse as configured by -max-idle-conns.
+func newBaseTransport(proxy func(*http.Request) (*url.URL, error), tlsClientConfig *tls.Config) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = proxy
+	t.TLSClientConfig = tlsClientConfig
+	t.MaxIdleConns = maxIdleConns
+	t.MaxIdleConnsPerHost = maxIdleConns
+	t.IdleConnTimeout = idleConnTimeout
+	t.DisableKeepAlives = maxIdleConns <= 0
+	return t
+}
+
+// newUpstreamClient returns the client for upstream requests, configured by
+// the command-line flags. Requests are sent through base.
+func newUpstreamClient(base *http.Transport, logger *slog.Logger) *http.Client {
+	if dryRun {
+		// Requests are never sent in dry-run mode, so they don't wait for
+		// the rate limiter, and aren't served from the cache either.
+		return &http.Client{Transport: dryRunTransport{}, CheckRedirect: checkRedirect}
+	}
+
+	// URLs taken from tool parameters and API records are fetched through a
+	// transport that only connects to public addresses.
+	var transport http.RoundTripper = publicURLTransport{next: base, public: newPublicTransport(base)}
+	transport = metricsTransport{next: decompressTransport{next: transport}}
+	transport = requestIDTransport{next: transport}
+	if header := upstreamHeaders(requestHeaders.header, authToken); len(header) > 0 {
+		// The base URL was validated by parseBaseURL.
+		u, _ := url.Parse(apiBaseURL)
+		transport = headerTransport{next: transport, host: u.Host, header: header}
+	}
+	transport = loggingTransport{next: transport, logger: logger}
+	if maxResponseBytes > 0 {
+		transport = limitTransport{next: transport, limit: maxResponseBytes}
+	}
+	// Cached responses don't count towards the rate limit.
+	transport = rateLimitTransport{next: transport, limiter: newRateLimiter(rateLimit, rateBurst)}
+	if cacheTTL > 0 {
+		transport = cachingTransport{next: transport, cache: newCache(), ttl: cacheTTL}
+	}
+	if includeHeaders {
+		// The base URL was validated by parseBaseURL.
+		u, _ := url.Parse(apiBaseURL)
+		names := append(slices.Clone(defaultIncludedHeaders), includedHeaders.names...)
+		transport = responseHeaderTransport{next: transport, host: u.Host, names: names}
+	}
+	return &http.Client{
+		Timeout:       httpTimeout,
+		Transport:     transport,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// validateListenAddr checks that addr is a valid `host:port` listen address.
+// The host may be empty, to listen on all interfaces, and the port may be 0, to
+// listen on an ephemeral port.
+func validateListenAddr(addr string) error {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 0 || n > 65535 {
+		return fmt.Errorf("invalid port %q in address %q", port, addr)
+	}
+	return nil
+}
+
+// sseBaseURL returns the base URL the SSE transport is advertised on. The host
+// is taken from the listen address (defaulting to "localhost") and the port
+// from the bound address, which differs when listening on port 0.
+func sseBaseURL(listenAddr string, boundAddr net.Addr) url.URL {
+	host, port, _ := net.SplitHostPort(listenAddr)
+	if host == "" {
+		host = "localhost"
+	}
+	if tcpAddr, ok := boundAddr.(*net.TCPAddr); ok {
+		port = strconv.Itoa(tcpAddr.Port)
+	}
+
+	return url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(host, port),
+	}
+}
+
+// advertisedURL returns the base URL the SSE transport is advertised on: the
+// public URL if set (see -public-url), otherwise the URL derived from the
+// listen address (see sseBaseURL).
+func advertisedURL(publicBaseURL *url.URL, listenAddr string, boundAddr net.Addr) url.URL {
+	if publicBaseURL != nil {
+		return *publicBaseURL
+	}
+	return sseBaseURL(listenAddr, boundAddr)
+}
+
+// parsePublicURL parses the public base URL of the server, which must be an
+// absolute `http` or `https` URL. An empty URL yields nil, meaning the URL is
+// derived from the listen address (see sseBaseURL).
+func parsePublicURL(rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("URL scheme %q is not allowed, must be http or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	u.Path = strings.TrimRight(u.Path, "/")
+
+	return u, nil
+}
 
-			apiURL := fmt.Sprintf("%v/apis?page=%d", apiBaseURL, page)
+// Maximum length of the tool name prefix, which keeps the longest prefixed
+// tool name within the 64 characters clients commonly allow.
+const maxToolPrefixLen = 32
+
+// validateToolPrefix checks that prefix only holds characters allowed in tool
+// names (letters, digits, underscores and dashes).
+func validateToolPrefix(prefix string) error {
+	if len(prefix) > maxToolPrefixLen {
+		return fmt.Errorf("prefix %q is longer than %d characters", prefix, maxToolPrefixLen)
+	}
+	for _, r := range prefix {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return fmt.Errorf("prefix %q contains invalid character %q", prefix, r)
+		}
+	}
+	return nil
+}
+
+// selectTools returns the tools named in allowlist, a comma-separated list of
+// tool names, in registration order. An empty allowlist selects all tools.
+// Unknown names are an error, so typos don't silently disable a tool.
+func selectTools(all []serverTool, allowlist string) ([]serverTool, error) {
+	if strings.TrimSpace(allowlist) == "" {
+		return all, nil
+	}
+
+	known := make(map[string]bool, len(all))
+	for _, t := range all {
+		known[t.name] = true
+	}
 
-			resp, err := http.Get(apiURL)
+	enabled := make(map[string]bool)
+	var unknown []string
+	for _, name := range strings.Split(allowlist, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case known[name]:
+			enabled[name] = true
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown tools: %v", strings.Join(unknown, ", "))
+	}
+
+	var tools []serverTool
+	for _, t := range all {
+		if enabled[t.name] {
+			tools = append(tools, t)
+		}
+	}
+	if len(tools) == 0 {
+		return nil, errors.New("no tools enabled")
+	}
+	return tools, nil
+}
+
+// resolveTransports returns the names of the enabled transports. At least one
+// transport must be enabled, otherwise the server can't be reached.
+func resolveTransports(stdio, sse bool) ([]string, error) {
+	var transports []string
+	if stdio {
+		transports = append(transports, "stdio")
+	}
+	if sse {
+		transports = append(transports, "sse")
+	}
+	if len(transports) == 0 {
+		return nil, errors.New("no transports enabled, enable at least one of `--stdio` (default) or `--sse`")
+	}
+	return transports, nil
+}
+
+// parseBaseURL validates that rawURL is an absolute URL, and returns it with
+// trailing slashes trimmed.
+func parseBaseURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return "", fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	return strings.TrimRight(rawURL, "/"), nil
+}
+
+func createListAPIsTool(client *http.Client, baseURL string) mcp.Tool {
+	return createTool(mcp.ToolDef[ListAPIsParams]{
+		Name: "list_apis",
+		Description: "List all APIs from the Developer Overheid API. Pages are numbered from 1 (the default). " +
+			"`perPage` optionally sets the page size (max 100). `organization` optionally keeps only the APIs of " +
+			"the organization with that name; it's applied to the fetched page, so a page may hold fewer APIs. " +
+			"`category` optionally keeps only the APIs in that category (see list_categories).",
+		HandleFunc: func(ctx context.Context, params ListAPIsParams) *mcp.CallToolResult {
+			page, err := resolvePage(params.Page)
 			if err != nil {
-				return newToolCallErrorResult("Error fetching APIs: %v", err)
+				return newToolCallErrorResult("Invalid page: %v", err)
 			}
-			defer resp.Body.Close()
 
-			var apis json.RawMessage
-			if err := json.NewDecoder(resp.Body).Decode(&apis); err != nil {
-				return newToolCallErrorResult("Error parsing response: %v", err)
+			apiURL, err := buildURL(baseURL, listAPIsQuery(page, params), "apis")
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
 			}
 
-			// Create response with APIs and next page info.
-			response := ListAPIsResponse{
-				APIs: apis,
+			lp, err := fetchList(ctx, client, apiURL, page)
+			if err != nil {
+				return newFetchErrorResult(ctx, "APIs", err)
 			}
+			apis := lp.Body
 
-			// Check for Link header to get the next page URL.
-			linkHeader := resp.Header.Get("Link")
-			if linkHeader != "" {
-				// Parse the Link header to find the "next" relation.
-				for _, link := range parseLinkHeader(linkHeader) {
-					if link.Rel != "next" {
-						continue
-					}
-					// Extract page number from URL.
-					parsedURL, err := url.Parse(link.URL)
-					if err == nil {
-						if pageStr := parsedURL.Query().Get("page"); pageStr != "" {
-							if nextPage, err := strconv.Atoi(pageStr); err == nil {
-								response.NextPage = nextPage
-							}
-						}
-					}
-					break
+			// The upstream API has no organization filter, so it's applied
+			// to the fetched page.
+			if org := strings.TrimSpace(params.Organization); org != "" {
+				apis, err = filterByOrganization(apis, org)
+				if err != nil {
+					return newToolCallErrorResult("Error filtering response: %v", err)
 				}
 			}
 
-			result, err := json.Marshal(response)
+			apis, err = normalizeRecords(apis)
 			if err != nil {
-				return newToolCallErrorResult("Error formatting response: %v", err)
+				return newToolCallErrorResult("Error normalizing response: %v", err)
 			}
 
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Text: string(result),
-					},
-				},
+			// Create response with APIs and pagination info.
+			response := ListAPIsResponse{
+				APIs:       apis,
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
 			}
+
+			return newListResult(response, apis, ListPagination{
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			})
 		},
 	})
 }
 
+// LinkRelation represents a link in a Link header.
 type LinkRelation struct {
 	URL string
 	Rel string
 }
 
+// parseLinkHeader parses a Link header (RFC 8288). A link with multiple
+// relation types (e.g. `rel="next last"`) yields a LinkRelation per type.
+// Relation types are lowercased, as they're compared case-insensitively.
+// Links without a `rel` parameter are skipped.
 func parseLinkHeader(header string) []LinkRelation {
 	var links []LinkRelation
 
-	// Split the header by comma to get individual link-value pairs
-	for _, link := range strings.Split(header, ",") {
-		link = strings.TrimSpace(link)
+	s := header
+	for {
+		// The target URI is enclosed in angle brackets, and may contain
+		// commas and semicolons.
+		start := strings.IndexByte(s, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(s[start:], '>')
+		if end < 0 {
+			break
+		}
+		target := strings.TrimSpace(s[start+1 : start+end])
 
-		// Extract URL and rel attributes using pre-compiled regexps
-		urlMatch := hateoasLinkRegexp.FindStringSubmatch(link)
-		relMatch := relRegexp.FindStringSubmatch(link)
+		var params map[string]string
+		params, s = parseLinkParams(s[start+end+1:])
 
-		if len(urlMatch) > 1 && len(relMatch) > 1 {
+		for _, rel := range strings.Fields(params["rel"]) {
 			links = append(links, LinkRelation{
-				URL: urlMatch[1],
-				Rel: relMatch[1],
+				URL: target,
+				Rel: strings.ToLower(rel),
 			})
 		}
 	}
@@ -257,45 +762,388 @@ func parseLinkHeader(header string) []LinkRelation {
 	return links
 }
 
+// parseLinkParams parses the `; name=value` parameters of a link-value, up to
+// the comma separating it from the next link-value. Values may be tokens or
+// quoted strings. Parameter names are lowercased; only the first occurrence of
+// a parameter counts. It returns the parameters and the remainder of s after
+// the separating comma.
+func parseLinkParams(s string) (map[string]string, string) {
+	params := make(map[string]string)
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return params, ""
+		}
+		switch s[0] {
+		case ',':
+			return params, s[1:]
+		case ';':
+			s = s[1:]
+		default:
+			// Malformed input: skip to the next link-value.
+			if i := strings.IndexAny(s, ",<"); i >= 0 && s[i] == ',' {
+				return params, s[i+1:]
+			} else if i >= 0 {
+				return params, s[i:]
+			}
+			return params, ""
+		}
+
+		s = strings.TrimLeft(s, " \t")
+		i := strings.IndexAny(s, "=;, \t")
+		if i < 0 {
+			i = len(s)
+		}
+		name := strings.ToLower(s[:i])
+		s = strings.TrimLeft(s[i:], " \t")
+
+		var value string
+		if strings.HasPrefix(s, "=") {
+			value, s = parseLinkParamValue(strings.TrimLeft(s[1:], " \t"))
+		}
+		if _, ok := params[name]; !ok && name != "" {
+			params[name] = value
+		}
+	}
+}
+
+// parseLinkParamValue parses a token or quoted-string value at the start of s,
+// and returns it along with the remainder of s.
+func parseLinkParamValue(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		i := strings.IndexAny(s, ";, \t")
+		if i < 0 {
+			return s, ""
+		}
+		return s[:i], s[i:]
+	}
+
+	var value strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				value.WriteByte(s[i])
+			}
+		case '"':
+			return value.String(), s[i+1:]
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	// Unterminated quoted string.
+	return value.String(), ""
+}
+
+// isTerminal reports whether f is an interactive terminal (character device).
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// joinURL joins path elements onto a base URL. Trailing slashes on the base URL
+// are stripped first, so the result never contains a double slash.
+func joinURL(baseURL string, elem ...string) (string, error) {
+	return url.JoinPath(strings.TrimRight(baseURL, "/"), elem...)
+}
+
+// isSuccessStatus reports whether an upstream HTTP status code is 2xx.
+func isSuccessStatus(code int) bool {
+	return code >= 200 && code <= 299
+}
+
+// Maximum length of the response body snippet included in upstream errors.
+const maxErrorSnippet = 200
+
+// checkStatus returns an error for a non-2xx upstream response, including the
+// status and a snippet of the response body. The error is in the errUpstream
+// category.
+func checkStatus(resp *http.Response) error {
+	if isSuccessStatus(resp.StatusCode) {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4*maxErrorSnippet))
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) > maxErrorSnippet {
+		snippet = strings.ToValidUTF8(snippet[:maxErrorSnippet], "") + "…"
+	}
+	err := fmt.Errorf("upstream returned %v", resp.Status)
+	if snippet != "" {
+		err = fmt.Errorf("upstream returned %v: %v", resp.Status, snippet)
+	}
+
+	return withCategory(errUpstream, statusError{code: resp.StatusCode, err: err})
+}
+
+// emptyList is the result of a list request that returned no content.
+var emptyList = json.RawMessage("[]")
+
+// errNoContent is returned by decodeJSONBody for a response without content,
+// when there's no empty value to return instead.
+var errNoContent = errors.New("upstream returned no content")
+
+// decodeJSONBody decodes the JSON body of a successful upstream response. A
+// response without content (204 No Content, or an empty 200 OK body) is
+// treated as an empty success: empty is returned instead of a decode error. If
+// empty is nil, errNoContent is returned. A response with a non-JSON content
+// type (e.g. an HTML error page) is rejected before decoding.
+func decodeJSONBody(resp *http.Response, empty json.RawMessage) (json.RawMessage, error) {
+	noContent := func() (json.RawMessage, error) {
+		if empty == nil {
+			return nil, errNoContent
+		}
+		return empty, nil
+	}
+
+	if resp.StatusCode == http.StatusNoContent || resp.ContentLength == 0 {
+		return noContent()
+	}
+	if err := checkJSONContentType(resp.Header.Get("Content-Type")); err != nil {
+		return nil, withCategory(errUpstream, err)
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if errors.Is(err, io.EOF) {
+			return noContent()
+		}
+		if errors.Is(err, errResponseTooLarge) {
+			return nil, withCategory(errUpstream, err)
+		}
+		return nil, withCategory(errDecode, err)
+	}
+
+	return body, nil
+}
+
+// checkJSONContentType checks that a response's content type is JSON
+// (`application/json` or a `+json` type). A missing content type is accepted.
+func checkJSONContentType(contentType string) error {
+	if contentType == "" {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("upstream returned invalid content type %q", contentType)
+	}
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return nil
+	}
+	if mediaType == "text/html" {
+		return errors.New("upstream returned an HTML page instead of JSON, possibly an error page")
+	}
+	return fmt.Errorf("upstream returned %v instead of JSON", mediaType)
+}
+
+// buildURL joins path elements onto a base URL (see joinURL) and appends the
+// encoded query, if any.
+func buildURL(baseURL string, query url.Values, elem ...string) (string, error) {
+	u, err := joinURL(baseURL, elem...)
+	if err != nil {
+		return "", err
+	}
+	if len(query) == 0 {
+		return u, nil
+	}
+	return u + "?" + query.Encode(), nil
+}
+
+// resolvePage returns the page number to request. Pages are numbered from 1;
+// an omitted page or page 0 means the first page. Negative pages are rejected,
+// rather than passed on to the upstream, which rejects them opaquely.
+func resolvePage(page *int) (int, error) {
+	if page == nil || *page == 0 {
+		return 1, nil
+	}
+	if *page < 0 {
+		return 0, fmt.Errorf("page must be >= 1 (page numbering is 1-based), got %d", *page)
+	}
+	return *page, nil
+}
+
+// pageQuery returns the query parameters for requesting a page of a list.
+func pageQuery(page int) url.Values {
+	return url.Values{"page": {strconv.Itoa(page)}}
+}
+
+// listQuery returns the query parameters for requesting a page of a list with
+// the given page size. A page size <= 0 leaves the upstream default in place;
+// larger page sizes are clamped to maxPerPage.
+func listQuery(page, perPage int) url.Values {
+	q := pageQuery(page)
+	if perPage > 0 {
+		q.Set("perPage", strconv.Itoa(min(perPage, maxPerPage)))
+	}
+	return q
+}
+
+// listAPIsQuery returns the query for fetching a page of APIs with the
+// parameters of the listAPIs tool. The category filter is applied upstream.
+func listAPIsQuery(page int, params ListAPIsParams) url.Values {
+	q := listQuery(page, params.PerPage)
+	if category := strings.TrimSpace(params.Category); category != "" {
+		q.Set("category", category)
+	}
+	return q
+}
+
+// marshalResult marshals the result of a tool call to JSON, indented if pretty
+// is set. Compact output saves tokens, especially for large lists.
+func marshalResult(v any, pretty bool) ([]byte, error) {
+	if pretty {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+// newToolCallErrorResult returns an error result with a formatted message. If
+// one of args is an error with a category (see errorCategory), the message is
+// prefixed with it, e.g. "[network] Error fetching APIs: ...". With
+// -pretty-errors, a remediation hint for the error is appended (see errorHint).
 func newToolCallErrorResult(format string, args ...any) *mcp.CallToolResult {
+	text := fmt.Sprintf(format, args...)
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			if category := errorCategory(err); category != "" {
+				text = "[" + category + "] " + text
+			}
+			if hint := errorHint(err); prettyErrors && hint != "" {
+				text += ". Hint: " + hint + "."
+			}
+			break
+		}
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			mcp.TextContent{
-				Text: fmt.Sprintf(format, args...),
+				Text: text,
 			},
 		},
 		IsError: true,
 	}
 }
 
-func createGetAPITool() mcp.Tool {
-	return mcp.CreateTool(mcp.ToolDef[GetAPIParams]{
-		Name:        "get_api",
-		Description: "Get a specific API by ID from the Developer Overheid API.",
+// newFetchErrorResult returns the error result for a failed upstream request.
+// When the tool call itself was canceled or timed out, that's reported instead
+// of the transport error it caused.
+func newFetchErrorResult(ctx context.Context, what string, err error) *mcp.CallToolResult {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return newToolCallErrorResult("Error fetching %v: tool call aborted: %v", what, ctxErr)
+	}
+	return newToolCallErrorResult("Error fetching %v: %v", what, err)
+}
+
+func createGetAPITool(client *http.Client, baseURL string) mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPIParams]{
+		Name: "get_api",
+		Description: `Get a specific API by ID from the Developer Overheid API. Optionally localized via "lang" ("nl" (default) or "en"). ` +
+			"For a deprecated API, a warning precedes the API, which gets a `_deprecation` field with the sunset date, if known.",
 		HandleFunc: func(ctx context.Context, params GetAPIParams) *mcp.CallToolResult {
-			url := fmt.Sprintf("%v/apis/%v", apiBaseURL, params.ID)
-			resp, err := http.Get(url)
+			id, err := validateID(params.ID)
 			if err != nil {
-				return newToolCallErrorResult("Error fetching API: %v", err)
+				return newToolCallErrorResult("Invalid id: %v", err)
+			}
+
+			lang := strings.ToLower(strings.TrimSpace(params.Lang))
+			if lang == "" {
+				lang = supportedLanguages[0]
+			}
+			if !slices.Contains(supportedLanguages, lang) {
+				return newToolCallErrorResult("Unsupported language %q, must be one of: %v", params.Lang, strings.Join(supportedLanguages, ", "))
+			}
+
+			resp, err := requestAPI(ctx, client, baseURL, id, lang)
+			if err != nil {
+				return newFetchErrorResult(ctx, "API", err)
+			}
+			if resp.StatusCode == http.StatusNotFound && idFallback {
+				// The ID may use another ID scheme (numeric or slug) than
+				// the one upstream currently uses.
+				altID, err := resolveAlternateID(ctx, id)
+				if err != nil {
+					resp.Body.Close()
+					return newToolCallErrorResult("Error resolving API ID %v: %v", id, err)
+				}
+				if altID != "" {
+					resp.Body.Close()
+					if resp, err = requestAPI(ctx, client, baseURL, altID, lang); err != nil {
+						return newFetchErrorResult(ctx, "API", err)
+					}
+				}
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == http.StatusNotFound {
-				return newToolCallErrorResult("API with ID %v not found", params.ID)
+				if lenientErrors {
+					return &mcp.CallToolResult{
+						Content: []mcp.Content{
+							mcp.TextContent{
+								Text: fmt.Sprintf("API with ID %v not found", id),
+							},
+						},
+					}
+				}
+				return newToolCallErrorResult("API with ID %v not found (%v)", id, checkStatus(resp))
+			}
+			if err := checkStatus(resp); err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
 			}
 
-			var api json.RawMessage
-			if err := json.NewDecoder(resp.Body).Decode(&api); err != nil {
+			api, err := decodeJSONBody(resp, nil)
+			if errors.Is(err, errNoContent) {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("API with ID %v exists, but upstream returned no content", id),
+						},
+					},
+				}
+			}
+			if err != nil {
 				return newToolCallErrorResult("Error parsing response: %v", err)
 			}
 
-			result, err := json.MarshalIndent(api, "", "  ")
+			// Detected before normalizing, which may rename fields.
+			deprecation := detectDeprecation(api, resp.Header)
+
+			api, err = normalizeRecords(api)
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
+			}
+
+			result, err := marshalResult(api, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			if deprecation == nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: string(result),
+						},
+					},
+				}
+			}
+
+			result, err = prependJSONField(result, "_deprecation", deprecation)
 			if err != nil {
 				return newToolCallErrorResult("Error formatting response: %v", err)
 			}
 
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: deprecationWarning(id, deprecation),
+					},
 					mcp.TextContent{
 						Text: string(result),
 					},
@@ -305,67 +1153,79 @@ func createGetAPITool() mcp.Tool {
 	})
 }
 
+// validateID trims an ID parameter and checks that it can be used as a path
+// segment of an upstream URL. IDs must still be escaped (see url.PathEscape).
+func validateID(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	switch id {
+	case "":
+		return "", errors.New("id parameter is required")
+	case ".", "..":
+		return "", fmt.Errorf("%q is not a valid id", id)
+	}
+	return id, nil
+}
+
+// requestAPI requests a single API record by ID, localized in lang. The caller
+// must close the response body.
+func requestAPI(ctx context.Context, client *http.Client, baseURL, id, lang string) (*http.Response, error) {
+	apiURL, err := joinURL(baseURL, "apis", url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("error building URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Accept-Language", lang)
+
+	return doWithRetry(ctx, client, req)
+}
+
 // createListRepositoriesTool creates a tool for listing repositories.
-func createListRepositoriesTool() mcp.Tool {
-	return mcp.CreateTool(mcp.ToolDef[ListRepositoriesParams]{
-		Name:        "list_repositories",
-		Description: "List all repositories from the Developer Overheid API.",
+func createListRepositoriesTool(client *http.Client, baseURL string) mcp.Tool {
+	return createTool(mcp.ToolDef[ListRepositoriesParams]{
+		Name: "list_repositories",
+		Description: "List all repositories from the Developer Overheid API. Pages are numbered from 1 (the default). " +
+			"`perPage` optionally sets the page size (max 100).",
 		HandleFunc: func(ctx context.Context, params ListRepositoriesParams) *mcp.CallToolResult {
-			page := params.Page
-			if page == 0 {
-				page = 1
+			page, err := resolvePage(params.Page)
+			if err != nil {
+				return newToolCallErrorResult("Invalid page: %v", err)
 			}
 
-			apiURL := fmt.Sprintf("%v/repositories?page=%v", apiBaseURL, page)
+			apiURL, err := buildURL(baseURL, listQuery(page, params.PerPage), "repositories")
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
+			}
 
-			resp, err := http.Get(apiURL)
+			lp, err := fetchList(ctx, client, apiURL, page)
 			if err != nil {
-				return newToolCallErrorResult("Error fetching repositories: %v", err)
+				return newFetchErrorResult(ctx, "repositories", err)
 			}
-			defer resp.Body.Close()
 
-			var repositories json.RawMessage
-			if err := json.NewDecoder(resp.Body).Decode(&repositories); err != nil {
-				return newToolCallErrorResult("Error parsing response: %v", err)
+			repositories, err := normalizeRecords(lp.Body)
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
 			}
 
-			// Create response with repositories and next page info.
+			// Create response with repositories and pagination info.
 			response := ListRepositoriesResponse{
 				Repositories: repositories,
+				NextPage:     lp.NextPage,
+				PrevPage:     lp.PrevPage,
+				LastPage:     lp.LastPage,
+				TotalCount:   lp.TotalCount,
 			}
 
-			// Check for Link header to get the next page URL.
-			linkHeader := resp.Header.Get("Link")
-			if linkHeader != "" {
-				// Parse the Link header to find the "next" relation
-				for _, link := range parseLinkHeader(linkHeader) {
-					if link.Rel == "next" {
-						// Extract page number from URL
-						parsedURL, err := url.Parse(link.URL)
-						if err == nil {
-							if pageStr := parsedURL.Query().Get("page"); pageStr != "" {
-								if nextPage, err := strconv.Atoi(pageStr); err == nil {
-									response.NextPage = nextPage
-								}
-							}
-						}
-						break
-					}
-				}
-			}
-
-			result, err := json.Marshal(response)
-			if err != nil {
-				return newToolCallErrorResult("Error formatting response: %v", err)
-			}
-
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{
-					mcp.TextContent{
-						Text: string(result),
-					},
-				},
-			}
+			return newListResult(response, repositories, ListPagination{
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			})
 		},
 	})
 }
@@ -0,0 +1,971 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// fixture is a canned upstream response served by a test server.
+type fixture struct {
+	status int // Defaults to 200 OK.
+	header http.Header
+	body   string
+}
+
+// serve writes the fixture to w. The content type defaults to JSON.
+func (f fixture) serve(w http.ResponseWriter) {
+	for k, v := range f.header {
+		w.Header()[k] = v
+	}
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(cmp.Or(f.status, http.StatusOK))
+	w.Write([]byte(f.body))
+}
+
+// newTestServer starts an httptest.Server running handler, and points
+// apiBaseURL and httpClient at it for the duration of the test. Like in main,
+// requests for public URLs go through a transport that only connects to
+// public addresses (see allowPrivateIPs). Retries are disabled, so error
+// fixtures are served once, and the page budget of catalog traversals is set
+// to its default.
+func newTestServer(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	oldBaseURL, oldClient, oldRetries, oldMaxPages := apiBaseURL, httpClient, maxRetries, maxPages
+	t.Cleanup(func() {
+		apiBaseURL, httpClient, maxRetries, maxPages = oldBaseURL, oldClient, oldRetries, oldMaxPages
+	})
+	apiBaseURL = srv.URL
+	httpClient = &http.Client{
+		Transport: publicURLTransport{
+			next:   srv.Client().Transport,
+			public: newPublicTransport(http.DefaultTransport.(*http.Transport)),
+		},
+		CheckRedirect: checkRedirect,
+	}
+	maxRetries = 0
+	maxPages = defaultMaxPages
+
+	return srv
+}
+
+// newFixtureServer starts a test server (see newTestServer) serving fixtures
+// by request path. Requests for other paths get a 404. Requests are recorded
+// in the returned slice, in order.
+func newFixtureServer(t *testing.T, fixtures map[string]fixture) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+
+	var requests []*http.Request
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		f, ok := fixtures[r.URL.Path]
+		if !ok {
+			f = fixture{status: http.StatusNotFound, body: `{"message":"not found"}`}
+		}
+		f.serve(w)
+	}))
+
+	return srv, &requests
+}
+
+// callTool calls tool with the JSON encoded args.
+func callTool(t *testing.T, tool mcp.Tool, args string) *mcp.CallToolResult {
+	t.Helper()
+
+	res, err := tool.HandleFunc(context.Background(), json.RawMessage(args))
+	if err != nil {
+		t.Fatalf("calling %v: %v", tool.Name, err)
+	}
+	return res
+}
+
+// resultText returns the text of the last text content item of res, which
+// holds the result document (earlier items may hold warnings).
+func resultText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+
+	for i := len(res.Content) - 1; i >= 0; i-- {
+		if text, ok := res.Content[i].(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	t.Fatalf("result has no text content: %#v", res.Content)
+	return ""
+}
+
+// decodeResult decodes the result document of a successful tool call into v.
+func decodeResult(t *testing.T, res *mcp.CallToolResult, v any) {
+	t.Helper()
+
+	text := resultText(t, res)
+	if res.IsError {
+		t.Fatalf("unexpected error result: %v", text)
+	}
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		t.Fatalf("decoding result %q: %v", text, err)
+	}
+}
+
+// expectError checks that res is an error result containing want.
+func expectError(t *testing.T, res *mcp.CallToolResult, want string) {
+	t.Helper()
+
+	text := resultText(t, res)
+	if !res.IsError {
+		t.Fatalf("expected error result, got %v", text)
+	}
+	if !strings.Contains(text, want) {
+		t.Errorf("error %q doesn't contain %q", text, want)
+	}
+}
+
+// listPageFixture returns a fixture for page of a list endpoint at path, with
+// Link relations for the given next, prev and last pages (0 omits one).
+func listPageFixture(path, body string, next, prev, last, total int) fixture {
+	var links []string
+	for _, l := range []struct {
+		rel  string
+		page int
+	}{{"next", next}, {"prev", prev}, {"last", last}} {
+		if l.page > 0 {
+			links = append(links, `<`+path+`?page=`+strconv.Itoa(l.page)+`>; rel="`+l.rel+`"`)
+		}
+	}
+
+	header := http.Header{}
+	if len(links) > 0 {
+		header.Set("Link", strings.Join(links, ", "))
+	}
+	if total > 0 {
+		header.Set("X-Total-Count", strconv.Itoa(total))
+	}
+
+	return fixture{header: header, body: body}
+}
+
+type listPageResult struct {
+	NextPage   int `json:"next_page"`
+	PrevPage   int `json:"prev_page"`
+	LastPage   int `json:"last_page"`
+	TotalCount int `json:"total_count"`
+}
+
+func TestListAPIs(t *testing.T) {
+	t.Run("success with pagination", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/apis": listPageFixture("/apis", `[{"id":"a"},{"id":"b"}]`, 3, 1, 5, 42),
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":2}`)
+
+		var got struct {
+			APIs []struct {
+				ID string `json:"id"`
+			} `json:"apis"`
+			listPageResult
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.APIs) != 2 || got.APIs[0].ID != "a" || got.APIs[1].ID != "b" {
+			t.Errorf("apis = %+v, want a and b", got.APIs)
+		}
+		want := listPageResult{NextPage: 3, PrevPage: 1, LastPage: 5, TotalCount: 42}
+		if got.listPageResult != want {
+			t.Errorf("pagination = %+v, want %+v", got.listPageResult, want)
+		}
+
+		if len(*requests) != 1 {
+			t.Fatalf("got %d upstream requests, want 1", len(*requests))
+		}
+		if page := (*requests)[0].URL.Query().Get("page"); page != "2" {
+			t.Errorf("requested page %q, want 2", page)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": listPageFixture("/apis", `[{"id":"a"}]`, 0, 4, 5, 0),
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":5}`)
+
+		var got listPageResult
+		decodeResult(t, res, &got)
+		if got.NextPage != 0 {
+			t.Errorf("next_page = %d on the last page, want 0", got.NextPage)
+		}
+		if got.PrevPage != 4 || got.LastPage != 5 {
+			t.Errorf("pagination = %+v, want prev 4 and last 5", got)
+		}
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusBadRequest, body: `{"message":"invalid page"}`},
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "400 Bad Request")
+		expectError(t, res, "invalid page")
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {body: `[{"id":`},
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[decode]")
+	})
+
+	t.Run("HTML error page", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {header: http.Header{"Content-Type": {"text/html"}}, body: `<html></html>`},
+		})
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "HTML page")
+	})
+}
+
+func TestGetAPI(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/apis/my api": {body: `{"id":"my api","title":"Test API"}`},
+		})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"my api","lang":"en"}`)
+
+		var got struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		}
+		decodeResult(t, res, &got)
+		if got.ID != "my api" || got.Title != "Test API" {
+			t.Errorf("got %+v, want the fixture record", got)
+		}
+
+		req := (*requests)[0]
+		if req.URL.EscapedPath() != "/apis/my%20api" {
+			t.Errorf("requested path %q, want the escaped ID", req.URL.EscapedPath())
+		}
+		if lang := req.Header.Get("Accept-Language"); lang != "en" {
+			t.Errorf("Accept-Language = %q, want en", lang)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		newFixtureServer(t, nil)
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+		expectError(t, res, "not found")
+	})
+
+	t.Run("not found with lenient errors", func(t *testing.T) {
+		newFixtureServer(t, nil)
+		oldLenient := lenientErrors
+		t.Cleanup(func() { lenientErrors = oldLenient })
+		lenientErrors = true
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+		if res.IsError {
+			t.Errorf("IsError = true for a 404 with -lenient-errors, want false")
+		}
+		if text, want := resultText(t, res), "API with ID missing not found"; text != want {
+			t.Errorf("result = %q, want %q", text, want)
+		}
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {status: http.StatusForbidden, body: `forbidden`},
+		})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		expectError(t, res, "403 Forbidden")
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis/a": {body: `{"id":"a",`},
+		})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		expectError(t, res, "Error parsing response")
+	})
+
+	t.Run("empty id", func(t *testing.T) {
+		_, requests := newFixtureServer(t, nil)
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"  "}`)
+		expectError(t, res, "id parameter is required")
+		if len(*requests) != 0 {
+			t.Errorf("got %d upstream requests for an empty id, want 0", len(*requests))
+		}
+	})
+}
+
+func TestListRepositories(t *testing.T) {
+	t.Run("success with pagination", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/repositories": listPageFixture("/repositories", `[{"id":"r1"}]`, 2, 0, 3, 21),
+		})
+
+		res := callTool(t, createListRepositoriesTool(httpClient, apiBaseURL), `{"perPage":500}`)
+
+		var got struct {
+			Repositories []struct {
+				ID string `json:"id"`
+			} `json:"repositories"`
+			listPageResult
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.Repositories) != 1 || got.Repositories[0].ID != "r1" {
+			t.Errorf("repositories = %+v, want r1", got.Repositories)
+		}
+		want := listPageResult{NextPage: 2, LastPage: 3, TotalCount: 21}
+		if got.listPageResult != want {
+			t.Errorf("pagination = %+v, want %+v", got.listPageResult, want)
+		}
+
+		query := (*requests)[0].URL.Query()
+		if query.Get("page") != "1" || query.Get("perPage") != strconv.Itoa(maxPerPage) {
+			t.Errorf("query = %v, want page 1 and perPage clamped to %d", query, maxPerPage)
+		}
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/repositories": {status: http.StatusNotFound, body: `{"message":"gone"}`},
+		})
+
+		res := callTool(t, createListRepositoriesTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "404 Not Found")
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/repositories": {body: `not json`},
+		})
+
+		res := callTool(t, createListRepositoriesTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[decode]")
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		_, requests := newFixtureServer(t, nil)
+
+		res := callTool(t, createListRepositoriesTool(httpClient, apiBaseURL), `{"page":-1}`)
+		expectError(t, res, "page must be >= 1")
+		if len(*requests) != 0 {
+			t.Errorf("got %d upstream requests for an invalid page, want 0", len(*requests))
+		}
+	})
+}
+
+func TestResolvePage(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		page    *int
+		want    int
+		wantErr bool
+	}{
+		{"omitted", nil, 1, false},
+		{"zero", intPtr(0), 1, false},
+		{"first", intPtr(1), 1, false},
+		{"large", intPtr(math.MaxInt32), math.MaxInt32, false},
+		{"negative", intPtr(-1), 0, true},
+		{"very negative", intPtr(math.MinInt), 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolvePage(tt.page)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "page must be >= 1") {
+					t.Errorf("resolvePage() error = %v, want page must be >= 1", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("resolvePage() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("tool call", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/apis": {body: `[]`},
+		})
+
+		expectError(t, callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":-3}`), "Invalid page")
+		if len(*requests) != 0 {
+			t.Errorf("got %d upstream requests for an invalid page, want 0", len(*requests))
+		}
+
+		callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":0}`)
+		callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{"page":1000000}`)
+		var pages []string
+		for _, r := range *requests {
+			pages = append(pages, r.URL.Query().Get("page"))
+		}
+		if !slices.Equal(pages, []string{"1", "1000000"}) {
+			t.Errorf("requested pages %q, want 1 and 1000000", pages)
+		}
+	})
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []LinkRelation
+	}{
+		{
+			name:   "empty header",
+			header: "",
+			want:   nil,
+		},
+		{
+			name:   "commas and semicolons in the target",
+			header: `<https://example.com/apis?q=a,b;c&page=2>; rel="next", <https://example.com/apis?page=1>; rel="first"`,
+			want: []LinkRelation{
+				{URL: "https://example.com/apis?q=a,b;c&page=2", Rel: "next"},
+				{URL: "https://example.com/apis?page=1", Rel: "first"},
+			},
+		},
+		{
+			name:   "quoted and token rel",
+			header: `</apis?page=2>; rel="next", </apis?page=5>;rel=last`,
+			want: []LinkRelation{
+				{URL: "/apis?page=2", Rel: "next"},
+				{URL: "/apis?page=5", Rel: "last"},
+			},
+		},
+		{
+			name:   "multiple space-separated rels",
+			header: `</apis?page=5>; rel="next last"`,
+			want: []LinkRelation{
+				{URL: "/apis?page=5", Rel: "next"},
+				{URL: "/apis?page=5", Rel: "last"},
+			},
+		},
+		{
+			name:   "mixed-case parameter name and rel",
+			header: `</apis?page=2>; REL="Next"`,
+			want:   []LinkRelation{{URL: "/apis?page=2", Rel: "next"}},
+		},
+		{
+			name:   "duplicate rel parameter",
+			header: `</apis?page=2>; rel="next"; rel="prev"`,
+			want:   []LinkRelation{{URL: "/apis?page=2", Rel: "next"}},
+		},
+		{
+			name:   "other parameters",
+			header: `</apis?page=2>; title="Page 2, of 5"; rel=next; type="application/json"`,
+			want:   []LinkRelation{{URL: "/apis?page=2", Rel: "next"}},
+		},
+		{
+			name:   "no rel",
+			header: `</apis?page=2>; title="next", </apis?page=1>; rel="prev"`,
+			want:   []LinkRelation{{URL: "/apis?page=1", Rel: "prev"}},
+		},
+		{
+			name:   "missing closing bracket",
+			header: `</apis?page=2; rel="next"`,
+			want:   nil,
+		},
+		{
+			name:   "missing closing bracket after a valid link",
+			header: `</apis?page=1>; rel="prev", </apis?page=3; rel="next"`,
+			want:   []LinkRelation{{URL: "/apis?page=1", Rel: "prev"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinkHeader(tt.header); !slices.Equal(got, tt.want) {
+				t.Errorf("parseLinkHeader(%q) = %+v, want %+v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkParseLinkHeader(b *testing.B) {
+	// A Link header as returned for a page in the middle of the catalog.
+	const header = `<https://apis.developer.overheid.nl/api/v0/apis?page=1&perPage=20>; rel="first", ` +
+		`<https://apis.developer.overheid.nl/api/v0/apis?page=4&perPage=20>; rel="prev", ` +
+		`<https://apis.developer.overheid.nl/api/v0/apis?page=6&perPage=20>; rel="next", ` +
+		`<https://apis.developer.overheid.nl/api/v0/apis?page=12&perPage=20>; rel="last"`
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if links := parseLinkHeader(header); len(links) != 4 {
+			b.Fatalf("got %d links, want 4", len(links))
+		}
+	}
+}
+
+func TestToolCallCanceledMidFlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		create func() mcp.Tool
+		args   string
+	}{
+		{"list_apis", func() mcp.Tool { return createListAPIsTool(httpClient, apiBaseURL) }, `{}`},
+		{"get_api", func() mcp.Tool { return createGetAPITool(httpClient, apiBaseURL) }, `{"id":"a"}`},
+		{"list_repositories", func() mcp.Tool { return createListRepositoriesTool(httpClient, apiBaseURL) }, `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := make(chan struct{})
+			newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(received)
+				// Block until the client gives up on the request.
+				<-r.Context().Done()
+			}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-received
+				cancel()
+			}()
+
+			done := make(chan *mcp.CallToolResult, 1)
+			go func() {
+				res, err := tt.create().HandleFunc(ctx, json.RawMessage(tt.args))
+				if err != nil {
+					t.Error(err)
+				}
+				done <- res
+			}()
+
+			select {
+			case res := <-done:
+				expectError(t, res, "tool call aborted: context canceled")
+			case <-time.After(5 * time.Second):
+				t.Fatal("tool call didn't return after its context was canceled")
+			}
+		})
+	}
+}
+
+func TestValidateListenAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: ":8080"},
+		{addr: ":0"},
+		{addr: "127.0.0.1:0"},
+		{addr: "[::1]:8080"},
+		{addr: "localhost:65535"},
+		{addr: "8080", wantErr: true},
+		{addr: ":http", wantErr: true},
+		{addr: ":65536", wantErr: true},
+		{addr: ":-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := validateListenAddr(tt.addr); (err != nil) != tt.wantErr {
+			t.Errorf("validateListenAddr(%q) = %v, want error: %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSSEBaseURL(t *testing.T) {
+	// Like main, listen on port 0 and advertise the port actually bound.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+	if port == "0" {
+		t.Fatal("listener bound to port 0")
+	}
+
+	tests := []struct {
+		listenAddr string
+		want       string
+	}{
+		{"127.0.0.1:0", "http://127.0.0.1:" + port},
+		{":0", "http://localhost:" + port},
+		{"[::1]:0", "http://[::1]:" + port},
+	}
+	for _, tt := range tests {
+		if got := sseBaseURL(tt.listenAddr, ln.Addr()); got.String() != tt.want {
+			t.Errorf("sseBaseURL(%q) = %v, want %v", tt.listenAddr, got.String(), tt.want)
+		}
+	}
+}
+
+func TestNewHTTPServer(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "base")
+
+	srv := newHTTPServer(ctx, ":0", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		v, _ := r.Context().Value(ctxKey{}).(string)
+		w.Write([]byte(v))
+	}))
+
+	if srv.ReadHeaderTimeout <= 0 || srv.IdleTimeout <= 0 {
+		t.Errorf("ReadHeaderTimeout = %v, IdleTimeout = %v; want both set", srv.ReadHeaderTimeout, srv.IdleTimeout)
+	}
+	// Read and write timeouts would cut off SSE streams.
+	if srv.ReadTimeout != 0 || srv.WriteTimeout != 0 {
+		t.Errorf("ReadTimeout = %v, WriteTimeout = %v; want neither set", srv.ReadTimeout, srv.WriteTimeout)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(ln)
+	t.Cleanup(func() { srv.Close() })
+
+	resp, err := http.Get("http://" + ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); string(body) != "base" {
+		t.Errorf("request context value = %q, want it derived from the base context", body)
+	}
+}
+
+func TestAdvertisedURL(t *testing.T) {
+	boundAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 41234}
+
+	tests := []struct {
+		publicURL string
+		want      string
+		wantErr   bool
+	}{
+		{publicURL: "", want: "http://localhost:41234"},
+		{publicURL: "https://mcp.example.org", want: "https://mcp.example.org"},
+		{publicURL: "https://mcp.example.org/register/", want: "https://mcp.example.org/register"},
+		{publicURL: "http://10.0.0.1:8443", want: "http://10.0.0.1:8443"},
+		{publicURL: "ftp://mcp.example.org", wantErr: true},
+		{publicURL: "/relative", wantErr: true},
+		{publicURL: "https://", wantErr: true},
+		{publicURL: "https://[::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.publicURL, func(t *testing.T) {
+			u, err := parsePublicURL(tt.publicURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePublicURL(%q) succeeded, want an error", tt.publicURL)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := advertisedURL(u, ":0", boundAddr); got.String() != tt.want {
+				t.Errorf("advertised URL = %v, want %v", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveTransports(t *testing.T) {
+	tests := []struct {
+		stdio, sse bool
+		want       []string
+	}{
+		{stdio: true, want: []string{"stdio"}},
+		{sse: true, want: []string{"sse"}},
+		{stdio: true, sse: true, want: []string{"stdio", "sse"}},
+	}
+	for _, tt := range tests {
+		got, err := resolveTransports(tt.stdio, tt.sse)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("resolveTransports(%v, %v) = %q, %v; want %q", tt.stdio, tt.sse, got, err, tt.want)
+		}
+	}
+
+	_, err := resolveTransports(false, false)
+	if err == nil {
+		t.Fatal("expected an error without transports")
+	}
+	for _, flag := range []string{"--stdio", "--sse"} {
+		if !strings.Contains(err.Error(), flag) {
+			t.Errorf("error %q doesn't mention %v", err, flag)
+		}
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if isTerminal(r) {
+		t.Error("pipe reported as terminal")
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "input"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if isTerminal(f) {
+		t.Error("regular file reported as terminal")
+	}
+
+	closed, err := os.Open(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	if isTerminal(closed) {
+		t.Error("closed file reported as terminal")
+	}
+
+	tty, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skipf("Opening pseudo-terminal: %v", err)
+	}
+	defer tty.Close()
+	if !isTerminal(tty) {
+		t.Error("pseudo-terminal not reported as terminal")
+	}
+}
+
+func TestSelectTools(t *testing.T) {
+	names := func(tools []serverTool) []string {
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.name)
+		}
+		return names
+	}
+
+	t.Run("all tools by default", func(t *testing.T) {
+		got, err := selectTools(serverTools, " ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(names(got), names(serverTools)) {
+			t.Errorf("tools = %q, want all tools", names(got))
+		}
+	})
+
+	t.Run("only the listed tools, in registration order", func(t *testing.T) {
+		got, err := selectTools(serverTools, "get_api, list_apis,")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"list_apis", "get_api"}; !slices.Equal(names(got), want) {
+			t.Errorf("tools = %q, want %q", names(got), want)
+		}
+		for _, tool := range got {
+			if created := tool.create(); created.Name != tool.name {
+				t.Errorf("tool %q creates tool %q", tool.name, created.Name)
+			}
+		}
+	})
+
+	t.Run("unknown tools", func(t *testing.T) {
+		_, err := selectTools(serverTools, "list_apis,get_apis,lsit_apis")
+		if err == nil || !strings.Contains(err.Error(), "get_apis, lsit_apis") {
+			t.Errorf("error = %v, want it to name the unknown tools", err)
+		}
+	})
+
+	t.Run("no tools", func(t *testing.T) {
+		if _, err := selectTools(serverTools, ","); err == nil {
+			t.Error("expected an error without tools")
+		}
+	})
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		elem    []string
+		want    string
+	}{
+		{"https://example.com/api/v1", []string{"apis"}, "https://example.com/api/v1/apis"},
+		{"https://example.com/api/v1/", []string{"apis"}, "https://example.com/api/v1/apis"},
+		{"https://example.com/api/v1//", []string{"apis", "a"}, "https://example.com/api/v1/apis/a"},
+		{"https://example.com/", []string{"apis"}, "https://example.com/apis"},
+		{"https://example.com/api/v1/", []string{"apis", url.PathEscape("a/b")}, "https://example.com/api/v1/apis/a%2Fb"},
+	}
+
+	for _, tt := range tests {
+		got, err := joinURL(tt.baseURL, tt.elem...)
+		if err != nil {
+			t.Errorf("joinURL(%q, %q) error: %v", tt.baseURL, tt.elem, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("joinURL(%q, %q) = %q, want %q", tt.baseURL, tt.elem, got, tt.want)
+		}
+	}
+
+	if _, err := joinURL("://example.com", "apis"); err == nil {
+		t.Error("expected an error for an invalid base URL")
+	}
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		fixture{body: `{"id":"a"}`}.serve(w)
+	}))
+
+	oldTimeout, oldRequestTimeout := httpTimeout, requestTimeout
+	t.Cleanup(func() { httpTimeout, requestTimeout = oldTimeout, oldRequestTimeout })
+	httpTimeout, requestTimeout = 50*time.Millisecond, 0
+	httpClient = newUpstreamClient(http.DefaultTransport.(*http.Transport).Clone(), slog.New(slog.DiscardHandler))
+
+	start := time.Now()
+	res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+	expectError(t, res, "[timeout]")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %v, want it to time out after %v", elapsed, httpTimeout)
+	}
+}
+
+func TestPerPageQuery(t *testing.T) {
+	_, requests := newFixtureServer(t, map[string]fixture{
+		"/apis":         listPageFixture("/apis", `[]`, 0, 0, 0, 0),
+		"/repositories": listPageFixture("/repositories", `[]`, 0, 0, 0, 0),
+	})
+
+	tools := []mcp.Tool{
+		createListAPIsTool(httpClient, apiBaseURL),
+		createListAPIsSummaryTool(httpClient, apiBaseURL),
+		createListRepositoriesTool(httpClient, apiBaseURL),
+	}
+	tests := []struct {
+		args string
+		want string
+	}{
+		{`{}`, "page=1"},
+		{`{"perPage":0}`, "page=1"},
+		{`{"perPage":-5}`, "page=1"},
+		{`{"page":3,"perPage":25}`, "page=3&perPage=25"},
+		{`{"perPage":100}`, "page=1&perPage=100"},
+		{`{"perPage":101}`, "page=1&perPage=100"},
+	}
+
+	for _, tool := range tools {
+		for _, tt := range tests {
+			*requests = nil
+			res := callTool(t, tool, tt.args)
+			if res.IsError {
+				t.Fatalf("%v %v: unexpected error result: %v", tool.Name, tt.args, resultText(t, res))
+			}
+			if got := (*requests)[0].URL.RawQuery; got != tt.want {
+				t.Errorf("%v %v: query = %q, want %q", tool.Name, tt.args, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestPrettyOutput(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis/a": {body: `{"id": "a",  "contact": {"email": "api@example.com"}}`},
+		"/apis":   {body: `[{"id": "a"}]`},
+	})
+
+	old := prettyOutput
+	t.Cleanup(func() { prettyOutput = old })
+
+	tests := []struct {
+		pretty bool
+		want   string
+	}{
+		{false, `{"id":"a","contact":{"email":"api@example.com"}}`},
+		{true, "{\n  \"id\": \"a\",\n  \"contact\": {\n    \"email\": \"api@example.com\"\n  }\n}"},
+	}
+	for _, tt := range tests {
+		prettyOutput = tt.pretty
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		if got := resultText(t, res); got != tt.want {
+			t.Errorf("result with pretty = %v: %q, want %q", tt.pretty, got, tt.want)
+		}
+
+		res = callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		if got := resultText(t, res); strings.Contains(got, "\n") != tt.pretty {
+			t.Errorf("list result with pretty = %v: %q", tt.pretty, got)
+		}
+	}
+}
+
+func TestConnectionReuse(t *testing.T) {
+	var remoteAddrs []string
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(remoteAddrs, r.RemoteAddr) {
+			remoteAddrs = append(remoteAddrs, r.RemoteAddr)
+		}
+		fixture{body: `{"id":"a"}`}.serve(w)
+	}))
+
+	oldIdle, oldTTL, oldLimit := maxIdleConns, cacheTTL, rateLimit
+	t.Cleanup(func() { maxIdleConns, cacheTTL, rateLimit = oldIdle, oldTTL, oldLimit })
+	cacheTTL, rateLimit = 0, 0
+
+	tests := []struct {
+		maxIdleConns int
+		wantConns    int
+	}{
+		{defaultMaxIdleConns, 1},
+		{0, 5},
+	}
+	for _, tt := range tests {
+		remoteAddrs = nil
+		maxIdleConns = tt.maxIdleConns
+		transport := newBaseTransport(nil, nil)
+		httpClient = newUpstreamClient(transport, slog.New(slog.DiscardHandler))
+
+		tool := createGetAPITool(httpClient, apiBaseURL)
+		for range 5 {
+			if res := callTool(t, tool, `{"id":"a"}`); res.IsError {
+				t.Fatalf("unexpected error result: %v", resultText(t, res))
+			}
+		}
+		transport.CloseIdleConnections()
+
+		if len(remoteAddrs) != tt.wantConns {
+			t.Errorf("5 calls with -max-idle-conns=%d used %d connections, want %d", tt.maxIdleConns, len(remoteAddrs), tt.wantConns)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry of the server's Prometheus metrics, served on `/metrics` when the
+// HTTP server runs (i.e. with the SSE transport).
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	toolCallsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_tool_calls_total",
+		Help: "Number of tool calls, by tool and result (ok or error).",
+	}, []string{"tool", "result"})
+
+	upstreamErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "mcp_upstream_errors_total",
+		Help: "Number of failed upstream requests (connection errors and 4xx/5xx responses), by tool.",
+	}, []string{"tool"})
+
+	upstreamRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mcp_upstream_request_duration_seconds",
+		Help:    "Latency of upstream requests, by tool and status code (\"error\" for connection errors).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool", "code"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		toolCallsTotal,
+		upstreamErrorsTotal,
+		upstreamRequestDuration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// metricsHandler serves the server's metrics in the Prometheus exposition
+// format.
+func metricsHandler() http.Handler {
+	return promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{})
+}
+
+// observeToolCall records the result of a tool call.
+func observeToolCall(tool string, isError bool) {
+	result := "ok"
+	if isError {
+		result = "error"
+	}
+	toolCallsTotal.WithLabelValues(tool, result).Inc()
+}
+
+// metricsTransport is an http.RoundTripper that records the latency and
+// failures of outbound requests, labeled by the calling tool.
+type metricsTransport struct {
+	next http.RoundTripper
+}
+
+func (t metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tool := toolFromContext(req.Context())
+	start := time.Now()
+
+	resp, err := t.next.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	upstreamRequestDuration.WithLabelValues(tool, code).Observe(time.Since(start).Seconds())
+	if err != nil || resp.StatusCode >= 400 {
+		upstreamErrorsTotal.WithLabelValues(tool).Inc()
+	}
+
+	return resp, err
+}
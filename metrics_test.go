@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// scrapeMetric scrapes /metrics and returns the value of the sample with the
+// given name and labels (as formatted in the exposition format, e.g.
+// `{tool="list_apis"}`), or 0 if there's none.
+func scrapeMetric(t *testing.T, sample string) float64 {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	metricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d", rec.Code)
+	}
+
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		value, ok := strings.CutPrefix(sc.Text(), sample+" ")
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Fatalf("parsing sample %q: %v", sc.Text(), err)
+		}
+		return v
+	}
+	return 0
+}
+
+func TestMetrics(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis": {body: `[]`},
+	})
+	httpClient = newUpstreamClient(http.DefaultTransport.(*http.Transport).Clone(), slog.New(slog.DiscardHandler))
+
+	samples := []string{
+		`mcp_tool_calls_total{result="ok",tool="list_apis"}`,
+		`mcp_tool_calls_total{result="error",tool="get_api"}`,
+		`mcp_upstream_errors_total{tool="get_api"}`,
+		`mcp_upstream_request_duration_seconds_count{code="200",tool="list_apis"}`,
+		`mcp_upstream_request_duration_seconds_count{code="404",tool="get_api"}`,
+	}
+	before := make(map[string]float64)
+	for _, s := range samples {
+		before[s] = scrapeMetric(t, s)
+	}
+
+	callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+	res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+	if !res.IsError {
+		t.Fatal("expected an error result for a missing API")
+	}
+
+	for _, s := range samples {
+		if got := scrapeMetric(t, s) - before[s]; got < 1 {
+			t.Errorf("%v increased by %v after the tool calls, want at least 1", s, got)
+		}
+	}
+}
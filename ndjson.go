@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Output formats of list tools.
+const (
+	outputFormatJSON   = "json"
+	outputFormatNDJSON = "ndjson"
+)
+
+// ListPagination represents the pagination info of a list tool result in
+// NDJSON output.
+type ListPagination struct {
+	NextPage   int `json:"next_page,omitempty"`
+	PrevPage   int `json:"prev_page,omitempty"`
+	LastPage   int `json:"last_page,omitempty"`
+	TotalCount int `json:"total_count,omitempty"`
+}
+
+// validateOutputFormat checks that format is a supported output format.
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputFormatJSON, outputFormatNDJSON:
+		return nil
+	}
+	return fmt.Errorf("invalid output format %q, must be %q or %q", format, outputFormatJSON, outputFormatNDJSON)
+}
+
+// newListResult returns the result of a list tool. By default, response is
+// returned as JSON. With `-output-format ndjson`, the items of the list are
+// returned as NDJSON (one compact JSON value per line) instead, followed by a
+// separate text content with the pagination info, if any.
+func newListResult(response any, items json.RawMessage, pagination ListPagination) *mcp.CallToolResult {
+	if outputFormat != outputFormatNDJSON {
+		result, err := marshalResult(response, prettyOutput)
+		if err != nil {
+			return newToolCallErrorResult("Error formatting response: %v", err)
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				mcp.TextContent{
+					Text: string(result),
+				},
+			},
+		}
+	}
+
+	lines, err := marshalNDJSON(items)
+	if err != nil {
+		return newToolCallErrorResult("Error formatting response: %v", err)
+	}
+
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{
+				Text: string(lines),
+			},
+		},
+	}
+	if pagination != (ListPagination{}) {
+		meta, err := json.Marshal(pagination)
+		if err != nil {
+			return newToolCallErrorResult("Error formatting response: %v", err)
+		}
+		result.Content = append(result.Content, mcp.TextContent{
+			Text: string(meta),
+		})
+	}
+
+	return result
+}
+
+// marshalNDJSON re-encodes a JSON array (or a wrapped list, see decodeItems)
+// as NDJSON: every element is compacted onto its own line.
+func marshalNDJSON(items json.RawMessage) ([]byte, error) {
+	elems, err := decodeItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, elem := range elems {
+		if err := json.Compact(&buf, elem); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+func setOutputFormat(t *testing.T, format string) {
+	t.Helper()
+
+	old := outputFormat
+	t.Cleanup(func() { outputFormat = old })
+	outputFormat = format
+}
+
+func TestNDJSONOutput(t *testing.T) {
+	const body = `[{"id":"a","title":"A","nested":{"x": [1, 2]}},{"id":"b","title":"B"},{"id":"c","title":"C"}]`
+
+	tests := []struct {
+		name string
+		tool func() mcp.Tool
+		path string
+		args string
+	}{
+		{"list_apis", func() mcp.Tool { return createListAPIsTool(httpClient, apiBaseURL) }, "/apis", `{}`},
+		{"list_apis_summary", func() mcp.Tool { return createListAPIsSummaryTool(httpClient, apiBaseURL) }, "/apis", `{}`},
+		{"search_apis", func() mcp.Tool { return createSearchAPIsTool(httpClient, apiBaseURL) }, "/apis", `{"query":"test"}`},
+		{"list_repositories", func() mcp.Tool { return createListRepositoriesTool(httpClient, apiBaseURL) }, "/repositories", `{}`},
+		{"list_organizations", createListOrganizationsTool, "/organizations", `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setOutputFormat(t, outputFormatNDJSON)
+			newFixtureServer(t, map[string]fixture{
+				tt.path: listPageFixture(tt.path, body, 2, 0, 2, 0),
+			})
+
+			res := callTool(t, tt.tool(), tt.args)
+			if res.IsError {
+				t.Fatalf("unexpected error result: %v", resultText(t, res))
+			}
+			if len(res.Content) != 2 {
+				t.Fatalf("got %d content items, want records and pagination", len(res.Content))
+			}
+
+			text := res.Content[0].(mcp.TextContent).Text
+			if !strings.HasSuffix(text, "\n") {
+				t.Errorf("NDJSON %q doesn't end with a newline", text)
+			}
+			lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+			if len(lines) != 3 {
+				t.Fatalf("got %d lines, want 3: %q", len(lines), text)
+			}
+			for i, line := range lines {
+				var rec map[string]any
+				if err := json.Unmarshal([]byte(line), &rec); err != nil {
+					t.Errorf("line %d %q doesn't parse: %v", i+1, line, err)
+					continue
+				}
+				if want := string(rune('a' + i)); rec["id"] != want {
+					t.Errorf("line %d has id %v, want %v", i+1, rec["id"], want)
+				}
+			}
+
+			var pagination listPageResult
+			if err := json.Unmarshal([]byte(res.Content[1].(mcp.TextContent).Text), &pagination); err != nil {
+				t.Fatalf("decoding pagination: %v", err)
+			}
+			if pagination.NextPage != 2 {
+				t.Errorf("next_page = %d, want 2", pagination.NextPage)
+			}
+		})
+	}
+}
+
+func TestMarshalNDJSON(t *testing.T) {
+	got, err := marshalNDJSON(json.RawMessage(`[ {"a": 1,
+		"b": [1, 2]}, 2, "x" ]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\"a\":1,\"b\":[1,2]}\n2\n\"x\"\n"; string(got) != want {
+		t.Errorf("marshalNDJSON() = %q, want %q", got, want)
+	}
+
+	if _, err := marshalNDJSON(json.RawMessage(`{"a":1}`)); err == nil {
+		t.Error("expected error for an object that isn't a list")
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Maximum size of an OpenAPI document that will be read into memory.
+const maxSpecBytes = 10 << 20
+
+// Duration for which decoded OpenAPI documents are cached.
+const specCacheTTL = 5 * time.Minute
+
+// Maximum number of decoded OpenAPI documents kept in the cache.
+const maxSpecCacheEntries = 100
+
+// Decoded OpenAPI documents, keyed by URL.
+var specs = newSpecCache()
+
+// specCache is a concurrency-safe cache of decoded OpenAPI documents.
+type specCache struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]specCacheEntry
+}
+
+// newSpecCache returns an empty spec cache.
+func newSpecCache() *specCache {
+	return &specCache{
+		now:     time.Now,
+		entries: make(map[string]specCacheEntry),
+	}
+}
+
+// get returns the document cached for specURL, if present and not expired.
+// An expired entry is removed.
+func (c *specCache) get(specURL string) (map[string]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[specURL]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, specURL)
+		return nil, false
+	}
+	return entry.spec, true
+}
+
+// put stores the document for specURL. When the cache is full, expired
+// entries are evicted first; if it's still full, spec isn't stored.
+func (c *specCache) put(specURL string, spec map[string]any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[specURL]; !ok && len(c.entries) >= maxSpecCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxSpecCacheEntries {
+			return
+		}
+	}
+
+	c.entries[specURL] = specCacheEntry{spec: spec, expiresAt: now.Add(specCacheTTL)}
+}
+
+type specCacheEntry struct {
+	spec      map[string]any
+	expiresAt time.Time
+}
+
+// Accept header for fetching OpenAPI documents, which are published as either
+// JSON or YAML.
+const specAccept = "application/json, application/yaml, application/x-yaml;q=0.9, text/yaml;q=0.9, */*;q=0.5"
+
+// HTTP methods that can hold an operation in an OpenAPI path item. These are
+// the same for Swagger 2.0 and OpenAPI 3.x (minus `trace` in Swagger 2.0).
+var oasMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OASOperationsSummaryParams represents the parameters for the oasOperationsSummary tool.
+// The `id` parameter is required.
+type OASOperationsSummaryParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// OASOperationsSummary represents the response from the oasOperationsSummary tool.
+type OASOperationsSummary struct {
+	SpecURL        string   `json:"spec_url"`
+	SpecVersion    string   `json:"spec_version"`
+	OperationCount int      `json:"operation_count"`
+	Tags           []string `json:"tags"`
+	Methods        []string `json:"methods"`
+}
+
+// createOASOperationsSummaryTool creates a tool for summarizing the operations
+// of an API's OpenAPI specification.
+func createOASOperationsSummaryTool() mcp.Tool {
+	return createTool(mcp.ToolDef[OASOperationsSummaryParams]{
+		Name:        "oas_operations_summary",
+		Description: "Summarize the OpenAPI specification of an API by ID: operation count, unique tags and supported HTTP methods.",
+		HandleFunc: func(ctx context.Context, params OASOperationsSummaryParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			specURL := specificationURL(api)
+			if specURL == "" {
+				return newToolCallErrorResult("API with ID %v has no OpenAPI specification URL", params.ID)
+			}
+
+			spec, err := fetchSpec(ctx, specURL)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching OpenAPI specification: %v", err)
+			}
+
+			summary := summarizeOperations(spec)
+			summary.SpecURL = specURL
+
+			result, err := marshalResult(summary, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// fetchAPI fetches a single API record by ID and decodes it into a map.
+func fetchAPI(ctx context.Context, id string) (map[string]any, error) {
+	raw, err := fetchAPIRaw(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var api map[string]any
+	if err := json.Unmarshal(raw, &api); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return api, nil
+}
+
+// fetchAPIRaw fetches a single API record by ID, as returned by upstream.
+func fetchAPIRaw(ctx context.Context, id string) (json.RawMessage, error) {
+	id, err := validateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL, err := joinURL(apiBaseURL, "apis", url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("error building URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := doWithRetry(ctx, httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, statusError{code: resp.StatusCode, err: fmt.Errorf("API with ID %v not found", id)}
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	raw, err := decodeJSONBody(resp, nil)
+	if errors.Is(err, errNoContent) {
+		return nil, fmt.Errorf("API with ID %v exists, but upstream returned no content", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return raw, nil
+}
+
+// specificationURL returns the OpenAPI specification URL of an API record. The
+// production environment is preferred over other environments.
+func specificationURL(api map[string]any) string {
+	envs, _ := api["environments"].([]any)
+
+	var fallback string
+	for _, v := range envs {
+		env, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		specURL, _ := env["specification_url"].(string)
+		if specURL == "" {
+			continue
+		}
+		if env["name"] == "production" {
+			return specURL
+		}
+		if fallback == "" {
+			fallback = specURL
+		}
+	}
+	if fallback != "" {
+		return fallback
+	}
+
+	for _, key := range []string{"specification_url", "oas_url", "oasUrl"} {
+		if specURL, ok := api[key].(string); ok && specURL != "" {
+			return specURL
+		}
+	}
+
+	return ""
+}
+
+// fetchSpec fetches an OpenAPI document and decodes it from either JSON or
+// YAML into a map.
+func fetchSpec(ctx context.Context, specURL string) (map[string]any, error) {
+	body, _, err := readSpec(ctx, specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeSpec(body)
+}
+
+// readSpec fetches the raw contents of an OpenAPI document, up to maxSpecBytes.
+// It returns the contents along with their media type, if any. Specification
+// URLs come from API records or tool parameters, so they must be public, see
+// newPublicURLRequest.
+func readSpec(ctx context.Context, specURL string) ([]byte, string, error) {
+	req, err := newPublicURLRequest(ctx, http.MethodGet, specURL)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", specAccept)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return nil, "", fmt.Errorf("spec server returned %v", resp.Status)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/html" {
+		return nil, "", errors.New("spec server returned an HTML page instead of an OpenAPI document")
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSpecBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > maxSpecBytes {
+		return nil, "", fmt.Errorf("specification exceeds %d bytes", maxSpecBytes)
+	}
+
+	return body, mediaType, nil
+}
+
+// fetchSpecCached calls fetchSpec, serving documents fetched within the last
+// specCacheTTL from memory.
+func fetchSpecCached(ctx context.Context, specURL string) (map[string]any, error) {
+	if spec, ok := specs.get(specURL); ok {
+		return spec, nil
+	}
+
+	spec, err := fetchSpec(ctx, specURL)
+	if err != nil {
+		return nil, err
+	}
+
+	specs.put(specURL, spec)
+
+	return spec, nil
+}
+
+// decodeSpec decodes an OpenAPI document, which may be either JSON or YAML.
+func decodeSpec(body []byte) (map[string]any, error) {
+	var spec map[string]any
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &spec); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return spec, nil
+	}
+
+	if err := yaml.Unmarshal(body, &spec); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if spec == nil {
+		return nil, errors.New("empty document")
+	}
+
+	return spec, nil
+}
+
+// summarizeOperations counts the operations in an OpenAPI 3.x or Swagger 2.0
+// document and collects their unique tags and HTTP methods.
+func summarizeOperations(spec map[string]any) OASOperationsSummary {
+	summary := OASOperationsSummary{
+		Tags:    []string{},
+		Methods: []string{},
+	}
+
+	if v, ok := spec["openapi"].(string); ok {
+		summary.SpecVersion = v
+	} else if v, ok := spec["swagger"].(string); ok {
+		summary.SpecVersion = v
+	}
+
+	paths, _ := spec["paths"].(map[string]any)
+	for _, v := range paths {
+		pathItem, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, method := range oasMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			summary.OperationCount++
+
+			if m := strings.ToUpper(method); !slices.Contains(summary.Methods, m) {
+				summary.Methods = append(summary.Methods, m)
+			}
+
+			tags, _ := op["tags"].([]any)
+			for _, t := range tags {
+				if tag, ok := t.(string); ok && !slices.Contains(summary.Tags, tag) {
+					summary.Tags = append(summary.Tags, tag)
+				}
+			}
+		}
+	}
+
+	slices.Sort(summary.Tags)
+	slices.Sort(summary.Methods)
+
+	return summary
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSpecCache(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := newSpecCache()
+	c.now = func() time.Time { return now }
+
+	c.put("a", map[string]any{"openapi": "3.0.0"})
+	if _, ok := c.get("a"); !ok {
+		t.Fatal("fresh entry not found")
+	}
+
+	now = now.Add(specCacheTTL)
+	if _, ok := c.get("a"); ok {
+		t.Error("expired entry returned")
+	}
+	if len(c.entries) != 0 {
+		t.Errorf("expired entry not removed on read, %d entries left", len(c.entries))
+	}
+}
+
+func TestSpecCacheLimit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := newSpecCache()
+	c.now = func() time.Time { return now }
+
+	for i := range maxSpecCacheEntries {
+		c.put(fmt.Sprint(i), map[string]any{})
+	}
+	c.put("full", map[string]any{})
+	if _, ok := c.get("full"); ok {
+		t.Error("entry stored in a full cache")
+	}
+	if len(c.entries) != maxSpecCacheEntries {
+		t.Errorf("got %d entries, want %d", len(c.entries), maxSpecCacheEntries)
+	}
+
+	// Once the entries have expired, they're evicted to make room.
+	now = now.Add(specCacheTTL)
+	c.put("new", map[string]any{})
+	if _, ok := c.get("new"); !ok {
+		t.Error("entry not stored after expired entries were evicted")
+	}
+	if len(c.entries) != 1 {
+		t.Errorf("got %d entries, want only the new one", len(c.entries))
+	}
+}
+
+func TestSummarizeOperations(t *testing.T) {
+	tests := []struct {
+		file        string
+		wantVersion string
+		wantCount   int
+		wantTags    []string
+		wantMethods []string
+	}{
+		{"openapi3.yaml", "3.0.3", 5, []string{"admin", "pets"}, []string{"DELETE", "GET", "POST"}},
+		{"swagger2.json", "2.0", 3, []string{"Billing", "orders"}, []string{"GET", "PATCH", "PUT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			body, err := os.ReadFile(filepath.Join("testdata", "oas", tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			spec, err := decodeSpec(body)
+			if err != nil {
+				t.Fatalf("decoding %v: %v", tt.file, err)
+			}
+
+			got := summarizeOperations(spec)
+			if got.SpecVersion != tt.wantVersion {
+				t.Errorf("spec version = %q, want %q", got.SpecVersion, tt.wantVersion)
+			}
+			if got.OperationCount != tt.wantCount {
+				t.Errorf("operation count = %d, want %d", got.OperationCount, tt.wantCount)
+			}
+			if !slices.Equal(got.Tags, tt.wantTags) {
+				t.Errorf("tags = %q, want %q", got.Tags, tt.wantTags)
+			}
+			if !slices.Equal(got.Methods, tt.wantMethods) {
+				t.Errorf("methods = %q, want %q", got.Methods, tt.wantMethods)
+			}
+		})
+	}
+}
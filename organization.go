@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// ListOrganizationsParams represents the parameters for the listOrganizations tool.
+// The `page` parameter is optional; pages are numbered from 1, and an omitted
+// page (or page 0) means the first page.
+type ListOrganizationsParams struct {
+	Page *int `json:"page,omitempty" jsonschema_description:"Page number, starting at 1. Defaults to 1 (also for 0)."`
+}
+
+// ListOrganizationsResponse represents the response from the listOrganizations tool.
+// Pagination is reported the same way as in ListAPIsResponse.
+type ListOrganizationsResponse struct {
+	Organizations json.RawMessage `json:"organizations"`
+	NextPage      int             `json:"next_page,omitempty"`
+	PrevPage      int             `json:"prev_page,omitempty"`
+	LastPage      int             `json:"last_page,omitempty"`
+	TotalCount    int             `json:"total_count,omitempty"`
+}
+
+// GetOrganizationParams represents the parameters for the getOrganization tool.
+// The `id` parameter is required.
+type GetOrganizationParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the organization."`
+}
+
+// createListOrganizationsTool creates a tool for listing organizations.
+func createListOrganizationsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListOrganizationsParams]{
+		Name:        "list_organizations",
+		Description: "List the organizations that own APIs and repositories in the Developer Overheid API. Pages are numbered from 1 (the default).",
+		HandleFunc: func(ctx context.Context, params ListOrganizationsParams) *mcp.CallToolResult {
+			page, err := resolvePage(params.Page)
+			if err != nil {
+				return newToolCallErrorResult("Invalid page: %v", err)
+			}
+
+			apiURL, err := buildURL(apiBaseURL, pageQuery(page), "organizations")
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
+			}
+
+			lp, err := fetchList(ctx, httpClient, apiURL, page)
+			if err != nil {
+				return newFetchErrorResult(ctx, "organizations", err)
+			}
+
+			organizations, err := normalizeRecords(lp.Body)
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
+			}
+
+			response := ListOrganizationsResponse{
+				Organizations: organizations,
+				NextPage:      lp.NextPage,
+				PrevPage:      lp.PrevPage,
+				LastPage:      lp.LastPage,
+				TotalCount:    lp.TotalCount,
+			}
+
+			return newListResult(response, organizations, ListPagination{
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			})
+		},
+	})
+}
+
+// createGetOrganizationTool creates a tool for getting an organization by ID.
+func createGetOrganizationTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetOrganizationParams]{
+		Name:        "get_organization",
+		Description: "Get a specific organization by ID from the Developer Overheid API.",
+		HandleFunc: func(ctx context.Context, params GetOrganizationParams) *mcp.CallToolResult {
+			id, err := validateID(params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Invalid id: %v", err)
+			}
+
+			apiURL, err := joinURL(apiBaseURL, "organizations", url.PathEscape(id))
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
+			}
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+			if err != nil {
+				return newToolCallErrorResult("Error creating request: %v", err)
+			}
+			req.Header.Set("Accept", "application/json")
+
+			resp, err := doWithRetry(ctx, httpClient, req)
+			if err != nil {
+				return newFetchErrorResult(ctx, "organization", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode == http.StatusNotFound {
+				return newToolCallErrorResult("Organization with ID %v not found (%v)", id, checkStatus(resp))
+			}
+			if err := checkStatus(resp); err != nil {
+				return newToolCallErrorResult("Error fetching organization: %v", err)
+			}
+
+			organization, err := decodeJSONBody(resp, nil)
+			if errors.Is(err, errNoContent) {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("Organization with ID %v exists, but upstream returned no content", id),
+						},
+					},
+				}
+			}
+			if err != nil {
+				return newToolCallErrorResult("Error parsing response: %v", err)
+			}
+
+			organization, err = normalizeRecords(organization)
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
+			}
+
+			result, err := marshalResult(organization, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
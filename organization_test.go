@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestListOrganizations(t *testing.T) {
+	t.Run("pagination", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Link", `</organizations?page=1>; rel="first", </organizations?page=1>; rel="prev", </organizations?page=3>; rel="next", </organizations?page=5>; rel="last"`)
+		header.Set("X-Total-Count", "42")
+		newFixtureServer(t, map[string]fixture{
+			"/organizations": {header: header, body: `[{"id":"o1","name":"Org"}]`},
+		})
+
+		res := callTool(t, createListOrganizationsTool(), `{"page":2}`)
+
+		var got struct {
+			Organizations []struct {
+				ID string `json:"id"`
+			} `json:"organizations"`
+			listPageResult
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.Organizations) != 1 || got.Organizations[0].ID != "o1" {
+			t.Errorf("organizations = %+v, want o1", got.Organizations)
+		}
+		want := listPageResult{NextPage: 3, PrevPage: 1, LastPage: 5, TotalCount: 42}
+		if got.listPageResult != want {
+			t.Errorf("pagination = %+v, want %+v", got.listPageResult, want)
+		}
+	})
+
+	t.Run("single page", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/organizations": {body: `[{"id":"o1"}]`},
+		})
+
+		res := callTool(t, createListOrganizationsTool(), `{}`)
+
+		var got map[string]any
+		decodeResult(t, res, &got)
+		for _, key := range []string{"next_page", "prev_page", "last_page", "total_count"} {
+			if _, ok := got[key]; ok {
+				t.Errorf("%s = %v without a Link or X-Total-Count header, want it omitted", key, got[key])
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{})
+
+		res := callTool(t, createListOrganizationsTool(), `{}`)
+		expectError(t, res, "404 Not Found")
+	})
+}
+
+func TestGetOrganization(t *testing.T) {
+	_, requests := newFixtureServer(t, map[string]fixture{
+		"/organizations/o 1": {body: `{"id":"o 1","name":"Org"}`},
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var got struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		decodeResult(t, callTool(t, createGetOrganizationTool(), `{"id":"o 1"}`), &got)
+		if got.ID != "o 1" || got.Name != "Org" {
+			t.Errorf("organization = %+v, want o 1", got)
+		}
+		if path := (*requests)[len(*requests)-1].URL.EscapedPath(); path != "/organizations/o%201" {
+			t.Errorf("requested %v, want the escaped ID", path)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		res := callTool(t, createGetOrganizationTool(), `{"id":"o2"}`)
+		expectError(t, res, "Organization with ID o2 not found")
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		n := len(*requests)
+		res := callTool(t, createGetOrganizationTool(), `{"id":" "}`)
+		expectError(t, res, "Invalid id")
+		if len(*requests) != n {
+			t.Error("requested an organization with an invalid id")
+		}
+	})
+}
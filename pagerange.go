@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Maximum number of pages fetched per listAPIsRange call.
+const maxRangePages = 10
+
+// ListAPIsRangeParams represents the parameters for the listAPIsRange tool.
+// The `startPage` and `endPage` parameters are required.
+type ListAPIsRangeParams struct {
+	StartPage int `json:"startPage" jsonschema:"required" jsonschema_description:"First page to fetch, starting at 1."`
+	EndPage   int `json:"endPage" jsonschema:"required" jsonschema_description:"Last page to fetch (inclusive), at most 10 pages after startPage."`
+}
+
+// ListAPIsRangeResponse represents the response from the listAPIsRange tool.
+type ListAPIsRangeResponse struct {
+	APIs         []json.RawMessage `json:"apis"`
+	PagesFetched int               `json:"pages_fetched"`
+	NextPage     int               `json:"next_page,omitempty"`
+}
+
+// createListAPIsRangeTool creates a tool for listing the APIs on a range of
+// pages, annotated with the page they were listed on.
+func createListAPIsRangeTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListAPIsRangeParams]{
+		Name: "list_apis_range",
+		Description: "List the APIs on a range of pages (`startPage` to `endPage`, inclusive, at most 10 pages) in a " +
+			"single call. Every API is annotated with the page it was listed on, in the `_page` field. Fetching " +
+			"stops early at the last page.",
+		HandleFunc: func(ctx context.Context, params ListAPIsRangeParams) *mcp.CallToolResult {
+			if params.StartPage < 1 {
+				return newToolCallErrorResult("Invalid startPage: %d, pages are numbered from 1", params.StartPage)
+			}
+			if params.EndPage < params.StartPage {
+				return newToolCallErrorResult("Invalid endPage: %d, must not be before startPage (%d)", params.EndPage, params.StartPage)
+			}
+			if span := params.EndPage - params.StartPage + 1; span > maxRangePages {
+				return newToolCallErrorResult("Range of %d pages is too large, must be at most %d pages", span, maxRangePages)
+			}
+
+			response := ListAPIsRangeResponse{
+				APIs: []json.RawMessage{},
+			}
+
+			page := params.StartPage
+			for page != 0 && page <= params.EndPage {
+				items, nextPage, err := fetchPageCached(ctx, "apis", page)
+				if err != nil {
+					return newToolCallErrorResult("Error fetching APIs: page %d: %v", page, err)
+				}
+				response.PagesFetched++
+
+				for _, item := range items {
+					item, err := normalizeRecords(item)
+					if err != nil {
+						return newToolCallErrorResult("Error normalizing response: %v", err)
+					}
+					item, err = prependJSONField(item, "_page", page)
+					if err != nil {
+						return newToolCallErrorResult("Error annotating response: %v", err)
+					}
+					response.APIs = append(response.APIs, item)
+				}
+
+				// Guard against a stale or looping "next" relation.
+				if nextPage <= page {
+					nextPage = 0
+				}
+				page = nextPage
+			}
+			// The first page beyond the range, if any.
+			response.NextPage = page
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestListAPIsRange(t *testing.T) {
+	pages := map[string]fixture{
+		"1": listPageFixture("/apis", `[{"id":"a"},{"id":"b"}]`, 2, 0, 3, 5),
+		"2": listPageFixture("/apis", `[{"id":"c"},{"id":"d"}]`, 3, 1, 3, 5),
+		"3": listPageFixture("/apis", `[{"id":"e"}]`, 0, 2, 3, 5),
+	}
+	var requested []string
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+		pages[page].serve(w)
+	}))
+
+	type annotatedAPI struct {
+		Page int    `json:"_page"`
+		ID   string `json:"id"`
+	}
+	type rangeResult struct {
+		APIs         []json.RawMessage `json:"apis"`
+		PagesFetched int               `json:"pages_fetched"`
+		NextPage     int               `json:"next_page"`
+	}
+
+	t.Run("annotates APIs with their page", func(t *testing.T) {
+		requested = nil
+		var got rangeResult
+		decodeResult(t, callTool(t, createListAPIsRangeTool(), `{"startPage":1,"endPage":2}`), &got)
+
+		want := []annotatedAPI{{1, "a"}, {1, "b"}, {2, "c"}, {2, "d"}}
+		var apis []annotatedAPI
+		for _, raw := range got.APIs {
+			var api annotatedAPI
+			if err := json.Unmarshal(raw, &api); err != nil {
+				t.Fatal(err)
+			}
+			apis = append(apis, api)
+
+			// The annotation comes first, so it's seen before the record.
+			if !bytes.HasPrefix(raw, []byte(`{"_page":`)) {
+				t.Errorf("API %s doesn't start with its page annotation", raw)
+			}
+		}
+		if !slices.Equal(apis, want) {
+			t.Errorf("APIs = %+v, want %+v", apis, want)
+		}
+		if got.PagesFetched != 2 || got.NextPage != 3 {
+			t.Errorf("pages fetched = %d, next page = %d; want 2 and 3", got.PagesFetched, got.NextPage)
+		}
+		if !slices.Equal(requested, []string{"1", "2"}) {
+			t.Errorf("requested pages %q, want 1 and 2", requested)
+		}
+	})
+
+	t.Run("stops at the last page", func(t *testing.T) {
+		requested = nil
+		var got rangeResult
+		decodeResult(t, callTool(t, createListAPIsRangeTool(), `{"startPage":3,"endPage":5}`), &got)
+
+		if len(got.APIs) != 1 || got.PagesFetched != 1 || got.NextPage != 0 {
+			t.Errorf("got %d APIs on %d pages, next page %d; want 1 API on the last page", len(got.APIs), got.PagesFetched, got.NextPage)
+		}
+		if !slices.Equal(requested, []string{"3"}) {
+			t.Errorf("requested pages %q, want only 3", requested)
+		}
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		requested = nil
+		for _, tt := range []struct {
+			args, want string
+		}{
+			{`{"startPage":-1,"endPage":2}`, "Invalid startPage: -1"},
+			{`{"startPage":3,"endPage":2}`, "Invalid endPage: 2"},
+			{`{"startPage":1,"endPage":11}`, "Range of 11 pages is too large"},
+		} {
+			expectError(t, callTool(t, createListAPIsRangeTool(), tt.args), tt.want)
+		}
+		if len(requested) > 0 {
+			t.Errorf("requested pages %q for invalid ranges", requested)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Fields of an API record that may hold rate limit or quota information.
+var rateLimitFields = []string{"rate_limit", "rate_limits", "quota", "quotas", "usage_policy"}
+
+// GetAPIUsagePolicyParams represents the parameters for the getAPIUsagePolicy tool.
+// The `id` parameter is required.
+type GetAPIUsagePolicyParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// UsagePolicy represents the response from the getAPIUsagePolicy tool.
+type UsagePolicy struct {
+	ID             string         `json:"id"`
+	HasPolicy      bool           `json:"has_policy"`
+	Authentication string         `json:"authentication,omitempty"`
+	TermsOfUse     map[string]any `json:"terms_of_use,omitempty"`
+	RateLimits     map[string]any `json:"rate_limits,omitempty"`
+	Note           string         `json:"note,omitempty"`
+}
+
+// createGetAPIUsagePolicyTool creates a tool for getting the usage policy
+// metadata of an API.
+func createGetAPIUsagePolicyTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPIUsagePolicyParams]{
+		Name: "get_api_usage_policy",
+		Description: "Get the usage policy metadata of an API by ID: authentication requirements, terms of use " +
+			"(e.g. government only, pay per use, uptime guarantee) and rate limits or quotas, when published.",
+		HandleFunc: func(ctx context.Context, params GetAPIUsagePolicyParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			policy := extractUsagePolicy(api)
+			policy.ID = params.ID
+
+			result, err := marshalResult(policy, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// extractUsagePolicy collects the usage policy fields of a decoded API record.
+func extractUsagePolicy(api map[string]any) UsagePolicy {
+	policy := UsagePolicy{
+		Authentication: recordString(api, "api_authentication", "authentication"),
+	}
+
+	if terms, ok := api["terms_of_use"].(map[string]any); ok && len(terms) > 0 {
+		policy.TermsOfUse = terms
+	}
+
+	for _, field := range rateLimitFields {
+		if v, ok := api[field]; ok && v != nil {
+			if policy.RateLimits == nil {
+				policy.RateLimits = make(map[string]any)
+			}
+			policy.RateLimits[field] = v
+		}
+	}
+
+	policy.HasPolicy = policy.Authentication != "" || policy.TermsOfUse != nil || policy.RateLimits != nil
+	if !policy.HasPolicy {
+		policy.Note = "The API record contains no usage policy metadata."
+	} else if policy.RateLimits == nil {
+		policy.Note = "No rate limits or quotas are published for this API."
+	}
+
+	return policy
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExtractUsagePolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		api  string
+		want UsagePolicy
+	}{
+		{
+			name: "no policy metadata",
+			api:  `{"id":"a","title":"A","terms_of_use":{},"quota":null}`,
+			want: UsagePolicy{Note: "The API record contains no usage policy metadata."},
+		},
+		{
+			name: "authentication only",
+			api:  `{"id":"a","api_authentication":"api_key"}`,
+			want: UsagePolicy{
+				HasPolicy:      true,
+				Authentication: "api_key",
+				Note:           "No rate limits or quotas are published for this API.",
+			},
+		},
+		{
+			name: "terms of use",
+			api:  `{"id":"a","authentication":"none","terms_of_use":{"government_only":true,"pay_per_use":false}}`,
+			want: UsagePolicy{
+				HasPolicy:      true,
+				Authentication: "none",
+				TermsOfUse:     map[string]any{"government_only": true, "pay_per_use": false},
+				Note:           "No rate limits or quotas are published for this API.",
+			},
+		},
+		{
+			name: "rate limits",
+			api:  `{"id":"a","rate_limit":"100/min","quotas":{"daily":10000}}`,
+			want: UsagePolicy{
+				HasPolicy:  true,
+				RateLimits: map[string]any{"rate_limit": "100/min", "quotas": map[string]any{"daily": float64(10000)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var api map[string]any
+			if err := json.Unmarshal([]byte(tt.api), &api); err != nil {
+				t.Fatal(err)
+			}
+			if got := extractUsagePolicy(api); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractUsagePolicy(%s) = %+v, want %+v", tt.api, got, tt.want)
+			}
+		})
+	}
+}
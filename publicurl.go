@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Hosts that URLs taken from tool parameters and API records may be fetched
+// from, set from -allowed-hosts. Empty allows any public host.
+var allowedHostList []string
+
+// parseAllowedHosts parses a comma-separated list of host names, as passed to
+// -allowed-hosts.
+func parseAllowedHosts(s string) ([]string, error) {
+	var hosts []string
+	for _, host := range strings.Split(s, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" {
+			continue
+		}
+		if strings.ContainsAny(host, ":/@") {
+			return nil, fmt.Errorf("invalid host %q: must be a bare host name, without scheme or port", host)
+		}
+		hosts = append(hosts, strings.TrimPrefix(host, "."))
+	}
+	return hosts, nil
+}
+
+// isAllowedHost reports whether host is allowed by allowedHostList: it equals
+// an allowed host, or is a subdomain of one.
+func isAllowedHost(host string) bool {
+	if len(allowedHostList) == 0 {
+		return true
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range allowedHostList {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Address ranges that aren't covered by the net.IP classification methods,
+// but aren't publicly routable either.
+var nonPublicNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "This" network.
+		"100.64.0.0/10", // Carrier-grade NAT.
+		"192.0.0.0/24",  // IETF protocol assignments.
+		"198.18.0.0/15", // Benchmarking.
+		"240.0.0.0/4",   // Reserved, including broadcast.
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isPublicIP reports whether ip is a publicly routable unicast address.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowIP decides which addresses URLs taken from tool parameters and API
+// records may be fetched from. Tests replace it to reach local test servers.
+var allowIP = isPublicIP
+
+// checkPublicHTTPURL checks that rawURL is an absolute `http` or `https` URL
+// whose host isn't a local or private address, and is allowed by
+// -allowed-hosts. Host names are only resolved when dialing, see
+// checkDialedAddr, or before sending a request through a proxy, see
+// checkResolvedHost.
+func checkPublicHTTPURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("URL scheme %q is not allowed", u.Scheme)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return errors.New("URL has no host")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("host %q is not allowed", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !allowIP(ip) {
+		return fmt.Errorf("host %q is not allowed", host)
+	}
+	if !isAllowedHost(host) {
+		return fmt.Errorf("host %q is not in the allowed hosts", host)
+	}
+
+	return nil
+}
+
+// checkDialedAddr is the net.Dialer control function of publicURLTransport. It
+// rejects connections to non-public addresses after name resolution, so a
+// host name resolving to a local or private address (e.g. through DNS
+// rebinding) can't be used to reach internal services.
+func checkDialedAddr(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !allowIP(ip) {
+		return fmt.Errorf("connection to %v is not allowed: not a public address", host)
+	}
+	return nil
+}
+
+// publicURLContextKey is the context key marking requests for URLs taken from
+// tool parameters and API records.
+type publicURLContextKey struct{}
+
+// contextWithPublicURL returns a copy of ctx marking requests made with it as
+// requests for URLs taken from tool parameters and API records, which must
+// only reach public hosts.
+func contextWithPublicURL(ctx context.Context) context.Context {
+	return context.WithValue(ctx, publicURLContextKey{}, true)
+}
+
+// isPublicURLRequest reports whether req was created by newPublicURLRequest.
+func isPublicURLRequest(req *http.Request) bool {
+	public, _ := req.Context().Value(publicURLContextKey{}).(bool)
+	return public
+}
+
+// newPublicURLRequest checks rawURL with checkPublicHTTPURL, and returns a
+// request for it that's sent through publicURLTransport. Its redirects are
+// checked too (see checkRedirect). Use it for every URL taken from a tool
+// parameter or an API record, rather than from -api-base-url.
+func newPublicURLRequest(ctx context.Context, method, rawURL string) (*http.Request, error) {
+	if err := checkPublicHTTPURL(rawURL); err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(contextWithPublicURL(ctx), method, rawURL, nil)
+}
+
+// publicURLTransport sends requests created by newPublicURLRequest through
+// public (see newPublicTransport), and all other requests through next.
+type publicURLTransport struct {
+	next   http.RoundTripper
+	public http.RoundTripper
+}
+
+func (t publicURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isPublicURLRequest(req) {
+		return t.public.RoundTrip(req)
+	}
+	return t.next.RoundTrip(req)
+}
+
+// publicTransport is the transport for requests created by
+// newPublicURLRequest. Requests for which a proxy is configured (see
+// proxyFunc) are sent through it; as the proxy connects to the target, its
+// host name is resolved and checked before the request instead (see
+// checkResolvedHost). Other requests connect directly, with the address
+// actually connected to checked by checkDialedAddr.
+type publicTransport struct {
+	direct  http.RoundTripper
+	proxied http.RoundTripper
+	proxy   func(*http.Request) (*url.URL, error)
+}
+
+func (t publicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.proxy != nil {
+		proxyURL, err := t.proxy(req)
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL != nil {
+			if err := checkResolvedHost(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+			return t.proxied.RoundTrip(req)
+		}
+	}
+	return t.direct.RoundTrip(req)
+}
+
+// lookupIPAddr resolves host names for checkResolvedHost. Tests replace it to
+// resolve names without DNS.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// checkResolvedHost checks that host only resolves to public addresses. It's
+// used for requests sent through a proxy, where the address connected to
+// can't be checked. The proxy resolves host itself, so a host name whose
+// address changes in between (e.g. through DNS rebinding) isn't caught.
+func checkResolvedHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !allowIP(ip) {
+			return fmt.Errorf("connection to %v is not allowed: not a public address", host)
+		}
+		return nil
+	}
+
+	addrs, err := lookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("resolving %v to check that it's a public host before sending the request through the proxy: %w", host, err)
+	}
+	if len(addrs) == 0 {
+		return fmt.Errorf("resolving %v: no addresses", host)
+	}
+	for _, addr := range addrs {
+		if !allowIP(addr.IP) {
+			return fmt.Errorf("connection to %v is not allowed: resolves to non-public address %v", host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// newPublicTransport returns the transport for requests created by
+// newPublicURLRequest, based on base: see publicTransport. The proxy of base,
+// if any, is used for the requests it applies to.
+func newPublicTransport(base *http.Transport) http.RoundTripper {
+	direct := base.Clone()
+	direct.Proxy = nil
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkDialedAddr,
+	}
+	direct.DialContext = dialer.DialContext
+
+	return publicTransport{
+		direct:  direct,
+		proxied: base,
+		proxy:   base.Proxy,
+	}
+}
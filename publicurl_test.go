@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// allowPrivateIPs allows URLs taken from tool parameters and API records to
+// be fetched from loopback addresses for the duration of the test, so they
+// can point at test servers.
+func allowPrivateIPs(t *testing.T) {
+	t.Helper()
+
+	old := allowIP
+	t.Cleanup(func() { allowIP = old })
+	allowIP = func(ip net.IP) bool { return ip.IsLoopback() || isPublicIP(ip) }
+}
+
+// setAllowedHosts sets the -allowed-hosts list for the duration of the test.
+func setAllowedHosts(t *testing.T, hosts ...string) {
+	t.Helper()
+
+	old := allowedHostList
+	t.Cleanup(func() { allowedHostList = old })
+	allowedHostList = hosts
+}
+
+func TestCheckPublicHTTPURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"https://example.com/contact", ""},
+		{"http://93.184.216.34/", ""},
+		{"ftp://example.com/", `scheme "ftp"`},
+		{"https:///path", "no host"},
+		{"http://localhost:8080/", "not allowed"},
+		{"http://api.localhost/", "not allowed"},
+		{"http://127.0.0.1/", "not allowed"},
+		{"http://[::1]/", "not allowed"},
+		{"http://10.0.0.1/", "not allowed"},
+		{"http://192.168.1.1/", "not allowed"},
+		{"http://169.254.169.254/latest/meta-data/", "not allowed"},
+		{"http://100.64.0.1/", "not allowed"},
+		{"http://0.0.0.0/", "not allowed"},
+		{"http://[fd00::1]/", "not allowed"},
+		{"http://[::ffff:127.0.0.1]/", "not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := checkPublicHTTPURL(tt.url)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckPublicHTTPURLAllowedHosts(t *testing.T) {
+	setAllowedHosts(t, "overheid.nl", "github.com")
+
+	for _, u := range []string{"https://overheid.nl/", "https://www.developer.overheid.nl/", "https://GitHub.com/x"} {
+		if err := checkPublicHTTPURL(u); err != nil {
+			t.Errorf("%v: unexpected error: %v", u, err)
+		}
+	}
+	for _, u := range []string{"https://example.com/", "https://notoverheid.nl/", "https://overheid.nl.example.com/"} {
+		if err := checkPublicHTTPURL(u); err == nil || !strings.Contains(err.Error(), "allowed hosts") {
+			t.Errorf("%v: error = %v, want a disallowed host error", u, err)
+		}
+	}
+}
+
+func TestParseAllowedHosts(t *testing.T) {
+	got, err := parseAllowedHosts(" Overheid.nl, .github.com,,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"overheid.nl", "github.com"}; !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, s := range []string{"https://overheid.nl", "overheid.nl:443", "user@overheid.nl"} {
+		if _, err := parseAllowedHosts(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestPublicTransportChecksDialedAddress(t *testing.T) {
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request for %v", r.URL)
+	}))
+
+	// The host check of checkPublicHTTPURL is bypassed by sending the
+	// request through the transport directly, as happens for host names
+	// resolving to a private address.
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newPublicTransport(http.DefaultTransport.(*http.Transport)).RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Errorf("error = %v, want a dial error for a non-public address", err)
+	}
+}
+
+func TestCheckRedirectToNonPublicURL(t *testing.T) {
+	allowPrivateIPs(t)
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://localhost/admin", http.StatusFound)
+	}))
+
+	req, err := newPublicURLRequest(t.Context(), http.MethodGet, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = httpClient.Do(req)
+	if err == nil || !strings.Contains(err.Error(), `host "localhost" is not allowed`) {
+		t.Errorf("error = %v, want a disallowed redirect error", err)
+	}
+}
+
+func TestValidateAPIContactRejectsPrivateURL(t *testing.T) {
+	var srvURL string
+	contactRequested := false
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/a":
+			fixture{body: `{"id":"a","contact":{"email":"api@example.com","url":"` + srvURL + `/contact"}}`}.serve(w)
+		case "/contact":
+			contactRequested = true
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	srvURL = srv.URL
+
+	res := callTool(t, createValidateAPIContactTool(), `{"id":"a"}`)
+
+	var got ContactValidationReport
+	decodeResult(t, res, &got)
+	if got.URL.Valid || !strings.Contains(got.URL.Error, "not allowed") {
+		t.Errorf("url = %+v, want it rejected as not allowed", got.URL)
+	}
+	if !got.Email.Valid {
+		t.Errorf("email = %+v, want valid", got.Email)
+	}
+	if contactRequested {
+		t.Error("contact URL on a loopback address was requested")
+	}
+}
+
+func TestValidateAPIContactFollowsPublicURL(t *testing.T) {
+	allowPrivateIPs(t)
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/a":
+			fixture{body: `{"id":"a","contact":{"url":"` + srvURL + `/contact"}}`}.serve(w)
+		case "/contact":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	srvURL = srv.URL
+
+	res := callTool(t, createValidateAPIContactTool(), `{"id":"a"}`)
+
+	var got ContactValidationReport
+	decodeResult(t, res, &got)
+	if !got.URL.Valid || got.URL.Status != http.StatusNoContent {
+		t.Errorf("url = %+v, want valid with status 204", got.URL)
+	}
+}
+
+// setLookupIPAddr resolves host names to the given addresses for the duration
+// of the test.
+func setLookupIPAddr(t *testing.T, addrs map[string]string) {
+	t.Helper()
+
+	old := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = old })
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		addr, ok := addrs[host]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []net.IPAddr{{IP: net.ParseIP(addr)}}, nil
+	}
+}
+
+func TestPublicTransportThroughProxy(t *testing.T) {
+	setLookupIPAddr(t, map[string]string{
+		"public.example":  "93.184.215.14",
+		"private.example": "10.0.0.1",
+	})
+
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		fixture{body: `{}`}.serve(w)
+	}))
+	t.Cleanup(proxy.Close)
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = http.ProxyURL(proxyURL)
+	transport := newPublicTransport(base)
+
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{"http://public.example/openapi.json", ""},
+		{"http://private.example/openapi.json", "resolves to non-public address 10.0.0.1"},
+		{"http://127.0.0.1/openapi.json", "not a public address"},
+		{"http://unknown.example/openapi.json", "resolving unknown.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			proxied = nil
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				if len(proxied) != 0 {
+					t.Errorf("request was sent to the proxy: %v", proxied)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if len(proxied) != 1 || proxied[0] != tt.url {
+				t.Errorf("proxied requests = %v, want %v", proxied, tt.url)
+			}
+		})
+	}
+}
+
+func TestPublicTransportWithoutProxyConnectsDirectly(t *testing.T) {
+	// A proxy func that applies to no request, like ProxyFromEnvironment
+	// without proxy environment variables.
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.Proxy = func(*http.Request) (*url.URL, error) { return nil, nil }
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request for %v", r.URL)
+	}))
+	t.Cleanup(srv.Close)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newPublicTransport(base).RoundTrip(req)
+	if err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Errorf("error = %v, want the dial check of a direct connection", err)
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// Default rate limit for outbound requests, in requests per second, and the
+// default burst size.
+const (
+	defaultRateLimit = 10
+	defaultRateBurst = 10
+)
+
+// newRateLimiter returns a limiter allowing limit requests per second, with
+// bursts of up to burst requests. A limit of 0 or less disables rate limiting.
+func newRateLimiter(limit float64, burst int) *rate.Limiter {
+	if limit <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(limit), max(burst, 1))
+}
+
+// rateLimitTransport is an http.RoundTripper that waits for its limiter
+// before sending a request. Waiting is aborted when the request's context is
+// canceled. The limiter is shared by all tools, so that the server as a whole
+// stays under the limit.
+type rateLimitTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
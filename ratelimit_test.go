@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     float64
+		burst     int
+		wantBurst int
+		// Number of requests allowed per second after the burst.
+		wantRate int
+	}{
+		{"limit and burst", 10, 5, 5, 10},
+		{"burst raised to 1", 2, 0, 1, 2},
+		{"disabled", 0, 5, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			l := newRateLimiter(tt.limit, tt.burst)
+
+			// Fire many calls at the same instant: only the burst passes.
+			allowed := 0
+			for range 1000 {
+				if l.AllowN(clock.Now(), 1) {
+					allowed++
+				}
+			}
+			if allowed != tt.wantBurst {
+				t.Errorf("allowed %d calls at once, want %d", allowed, tt.wantBurst)
+			}
+
+			// Spread over a second, calls are allowed at the limit.
+			allowed = 0
+			for range 1000 {
+				clock.Advance(time.Millisecond)
+				if l.AllowN(clock.Now(), 1) {
+					allowed++
+				}
+			}
+			if allowed != tt.wantRate {
+				t.Errorf("allowed %d calls in the following second, want %d", allowed, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+
+	// With one request per hour, only the burst can be sent; further requests
+	// fail right away, since their wait would exceed the context deadline.
+	const burst = 3
+	rt := rateLimitTransport{next: http.DefaultTransport, limiter: newRateLimiter(1.0/3600, burst)}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	failed := 0
+	for range 10 {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			failed++
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	if n := requests.Load(); n != burst {
+		t.Errorf("sent %d requests, want the burst of %d", n, burst)
+	}
+	if failed != 10-burst {
+		t.Errorf("%d requests failed, want %d", failed, 10-burst)
+	}
+}
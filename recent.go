@@ -0,0 +1,138 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Limits for the number of APIs returned by the listRecentAPIs tool.
+const (
+	defaultRecentLimit = 10
+	maxRecentLimit     = 100
+)
+
+// Fields of an API record that may hold its creation date, in order of
+// preference.
+var creationDateFields = []string{"created_at", "registered_at", "createdAt", "created", "date_created"}
+
+// ListRecentAPIsParams represents the parameters for the listRecentAPIs tool.
+// The `limit` parameter is optional.
+type ListRecentAPIsParams struct {
+	Limit int `json:"limit,omitempty" jsonschema_description:"Maximum number of APIs returned, at most 100. Defaults to 10."`
+}
+
+// ListRecentAPIsResponse represents the response from the listRecentAPIs tool.
+type ListRecentAPIsResponse struct {
+	APIs         []json.RawMessage `json:"apis"`
+	Count        int               `json:"count"`
+	Undated      int               `json:"undated,omitempty"`
+	PagesScanned int               `json:"pages_scanned"`
+	Truncated    bool              `json:"truncated,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// datedAPI is an API record along with its creation date.
+type datedAPI struct {
+	item      json.RawMessage
+	id        string
+	createdAt time.Time
+}
+
+// createListRecentAPIsTool creates a tool for listing the most recently added
+// APIs.
+func createListRecentAPIsTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListRecentAPIsParams]{
+		Name: "list_recent_apis",
+		Description: "List the most recently added APIs, newest first, by their creation (registration) date. " +
+			"`limit` defaults to 10 (max 100). APIs without a creation date are skipped and counted as `undated`. " +
+			"The number of pages scanned is bounded; when truncated, older parts of the catalog may be missing.",
+		HandleFunc: func(ctx context.Context, params ListRecentAPIsParams) *mcp.CallToolResult {
+			limit := params.Limit
+			if limit == 0 {
+				limit = defaultRecentLimit
+			}
+			if limit < 1 || limit > maxRecentLimit {
+				return newToolCallErrorResult("Invalid limit %d, must be between 1 and %d", params.Limit, maxRecentLimit)
+			}
+
+			// The upstream API has no sort parameter, so the catalog is
+			// sorted client-side.
+			var apis []datedAPI
+			response := ListRecentAPIsResponse{}
+
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+				createdAt, ok := creationDate(rec)
+				if !ok {
+					response.Undated++
+					return nil
+				}
+				apis = append(apis, datedAPI{item: item, id: apiID(rec), createdAt: createdAt})
+				return nil
+			})
+			if err != nil {
+				if !errors.Is(err, errPageTimeout) {
+					return newToolCallErrorResult("Error fetching APIs: %v", err)
+				}
+				response.Error = err.Error()
+				more = true
+			}
+
+			slices.SortStableFunc(apis, func(a, b datedAPI) int {
+				return cmp.Or(b.createdAt.Compare(a.createdAt), cmp.Compare(a.id, b.id))
+			})
+
+			response.APIs = make([]json.RawMessage, 0, min(limit, len(apis)))
+			for _, api := range apis[:min(limit, len(apis))] {
+				item, err := normalizeRecords(api.item)
+				if err != nil {
+					return newToolCallErrorResult("Error normalizing response: %v", err)
+				}
+				response.APIs = append(response.APIs, item)
+			}
+
+			response.Count = len(response.APIs)
+			response.PagesScanned = pages
+			response.Truncated = more
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// creationDate returns the creation date of a decoded API record. Both full
+// RFC 3339 timestamps and plain dates are accepted.
+func creationDate(rec map[string]any) (time.Time, bool) {
+	for _, field := range creationDateFields {
+		s, ok := rec[field].(string)
+		if !ok || s == "" {
+			continue
+		}
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
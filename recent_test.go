@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestListRecentAPIs(t *testing.T) {
+	body := `[
+		{"id":"old","created_at":"2023-01-15"},
+		{"id":"noon","created_at":"2024-06-01T12:00:00Z"},
+		{"id":"undated"},
+		{"id":"midnight","created_at":"2024-06-01"},
+		{"id":"bad-date","created_at":"June 2024"},
+		{"id":"offset","registered_at":"2024-06-01T12:00:00+02:00"},
+		{"id":"tie","created_at":"2024-06-01T12:00:00Z"}
+	]`
+	newFixtureServer(t, map[string]fixture{"/apis": {body: body}})
+
+	// "offset" is 10:00 UTC, before "noon" and after "midnight"; APIs with
+	// the same date are ordered by ID.
+	wantOrder := []string{"noon", "tie", "offset", "midnight", "old"}
+
+	tests := []struct {
+		args string
+		want []string
+	}{
+		{`{}`, wantOrder},
+		{`{"limit":2}`, wantOrder[:2]},
+		{`{"limit":1}`, wantOrder[:1]},
+		{`{"limit":100}`, wantOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			res := callTool(t, createListRecentAPIsTool(), tt.args)
+
+			var got struct {
+				APIs []struct {
+					ID string `json:"id"`
+				} `json:"apis"`
+				Count   int `json:"count"`
+				Undated int `json:"undated"`
+			}
+			decodeResult(t, res, &got)
+
+			var ids []string
+			for _, api := range got.APIs {
+				ids = append(ids, api.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("APIs = %v, want %v", ids, tt.want)
+			}
+			if got.Count != len(tt.want) {
+				t.Errorf("count = %d, want %d", got.Count, len(tt.want))
+			}
+			if got.Undated != 2 {
+				t.Errorf("undated = %d, want 2", got.Undated)
+			}
+		})
+	}
+
+	for _, limit := range []int{-1, maxRecentLimit + 1} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			res := callTool(t, createListRecentAPIsTool(), fmt.Sprintf(`{"limit":%d}`, limit))
+			expectError(t, res, "Invalid limit")
+		})
+	}
+}
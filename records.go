@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// recordString returns the first non-empty string value of the given keys in
+// a decoded record. Numeric values are formatted as strings.
+func recordString(rec map[string]any, keys ...string) string {
+	for _, key := range keys {
+		switch v := rec[key].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case float64:
+			return fmt.Sprintf("%v", v)
+		}
+	}
+	return ""
+}
+
+// recordStrings returns the string values of a list field in a decoded record.
+// A single string value is returned as a one-element slice.
+func recordStrings(rec map[string]any, key string) []string {
+	switch v := rec[key].(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []any:
+		var values []string
+		for _, elem := range v {
+			if s, ok := elem.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// apiID returns the ID of a decoded API (or repository) record.
+func apiID(rec map[string]any) string {
+	return recordString(rec, "id", "api_id")
+}
+
+// apiName returns the display name of a decoded API record.
+func apiName(rec map[string]any) string {
+	return recordString(rec, "service_name", "title", "name")
+}
+
+// apiType returns the type (protocol) of a decoded API record.
+func apiType(rec map[string]any) string {
+	return recordString(rec, "api_type", "type")
+}
+
+// apiOrganization returns the organization name of a decoded API record. The
+// organization may be either a nested object or a plain string.
+func apiOrganization(rec map[string]any) string {
+	if org, ok := rec["organization"].(map[string]any); ok {
+		return recordString(org, "name", "label", "ooid")
+	}
+	return recordString(rec, "organization", "organization_name")
+}
+
+// apiCategories returns the (unique) categories of a decoded API record.
+// Categories may be strings or objects with a name.
+func apiCategories(rec map[string]any) []string {
+	var categories []string
+	add := func(v any) {
+		var name string
+		switch v := v.(type) {
+		case string:
+			name = strings.TrimSpace(v)
+		case map[string]any:
+			name = recordString(v, "name", "label", "title")
+		}
+		if name != "" && !slices.Contains(categories, name) {
+			categories = append(categories, name)
+		}
+	}
+
+	for _, key := range []string{"categories", "category"} {
+		switch v := rec[key].(type) {
+		case []any:
+			for _, elem := range v {
+				add(elem)
+			}
+		default:
+			add(v)
+		}
+	}
+
+	return categories
+}
+
+// containsFold reports whether substr is within s, ignoring case.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// canonicalJSON re-encodes a JSON document with object keys sorted and
+// insignificant whitespace removed, so that equal documents yield equal bytes
+// regardless of upstream key ordering. Numbers are preserved verbatim.
+func canonicalJSON(raw json.RawMessage) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(v)
+}
+
+// normalizeRecords applies the configured output normalization to a JSON
+// document holding one or more records. It returns raw unchanged unless
+// -collapse-whitespace or -compact-records is set.
+func normalizeRecords(raw json.RawMessage) (json.RawMessage, error) {
+	if !collapseWhitespace && !compactRecords {
+		return raw, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(normalizeValue(v))
+}
+
+// normalizeValue applies the configured output normalization to a decoded JSON
+// value. Maps may be modified in place.
+func normalizeValue(v any) any {
+	if collapseWhitespace {
+		v = collapseSpace(v)
+	}
+	if compactRecords {
+		v = compactValue(v)
+	}
+	return v
+}
+
+// collapseSpace replaces runs of whitespace in all string values of a decoded
+// JSON value with a single space, and trims leading and trailing whitespace.
+func collapseSpace(v any) any {
+	switch v := v.(type) {
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case map[string]any:
+		for k, elem := range v {
+			v[k] = collapseSpace(elem)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = collapseSpace(elem)
+		}
+	}
+	return v
+}
+
+// prependJSONField returns a JSON object with a field name holding value
+// prepended to its fields, keeping the order (and formatting) of the other
+// fields.
+func prependJSONField(obj json.RawMessage, name string, value any) (json.RawMessage, error) {
+	obj = bytes.TrimSpace(obj)
+	if len(obj) < 2 || obj[0] != '{' {
+		return nil, errors.New("expected a JSON object")
+	}
+
+	field, err := json.Marshal(map[string]any{name: value})
+	if err != nil {
+		return nil, err
+	}
+
+	// Splice the fields of the single-field object into obj.
+	out := field[:len(field)-1]
+	if rest := bytes.TrimSpace(obj[1:]); rest[0] != '}' {
+		out = append(out, ',')
+		return append(out, rest...), nil
+	}
+	return append(out, '}'), nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCollapseSpace(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"string", `"  An\n\tAPI   for  data \r\n"`, `"An API for data"`},
+		{"nested values", `{"a":" x  y ","b":[" z ",{"c":"\n"}]}`, `{"a":"x y","b":["z",{"c":""}]}`},
+		{"non-strings", `{"n":1.50,"ok":true,"nil":null}`, `{"n":1.50,"nil":null,"ok":true}`},
+		{"keys untouched", `{" a  b ":"c"}`, `{" a  b ":"c"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeRecordsWith(t, true, tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// normalizeRecordsWith normalizes raw with -collapse-whitespace set to
+// collapse, returning the result as a string.
+func normalizeRecordsWith(t *testing.T, collapse bool, raw string) (string, error) {
+	t.Helper()
+
+	old := collapseWhitespace
+	t.Cleanup(func() { collapseWhitespace = old })
+	collapseWhitespace = collapse
+
+	got, err := normalizeRecords(json.RawMessage(raw))
+	return string(got), err
+}
+
+func TestNormalizeRecordsUnchanged(t *testing.T) {
+	const raw = `{"description":"  An\n  API  "}`
+	got, err := normalizeRecordsWith(t, false, raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != raw {
+		t.Errorf("got %s, want the record unchanged without -collapse-whitespace", got)
+	}
+}
+
+func TestGetAPICollapsesWhitespace(t *testing.T) {
+	newFixtureServer(t, map[string]fixture{
+		"/apis/a": {body: `{"id":"a","description":"Line one.\n\n  Line   two.\t"}`},
+	})
+
+	old := collapseWhitespace
+	t.Cleanup(func() { collapseWhitespace = old })
+	collapseWhitespace = true
+
+	var got struct {
+		Description string `json:"description"`
+	}
+	decodeResult(t, callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`), &got)
+	if want := "Line one. Line two."; got.Description != want {
+		t.Errorf("description = %q, want %q", got.Description, want)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// Maximum number of redirects followed for a single upstream request.
+const maxRedirects = 5
+
+// checkRedirect is the redirect policy of the upstream HTTP client. It stops
+// after maxRedirects redirects or when a redirect loops back to a URL visited
+// before, and strips credential headers (see isCredentialHeader) on redirects
+// to another host. Headers configured with -header and -auth-token are added
+// by headerTransport, for the API host only, so they aren't forwarded either.
+// Redirects of requests for public URLs (see newPublicURLRequest) must lead
+// to a public URL as well.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	// via holds the original request and the redirects followed so far.
+	if len(via) > maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	for _, prev := range via {
+		if prev.URL.String() == req.URL.String() {
+			return fmt.Errorf("redirect loop at %v", req.URL.Redacted())
+		}
+	}
+
+	if isPublicURLRequest(req) {
+		if err := checkPublicHTTPURL(req.URL.String()); err != nil {
+			return fmt.Errorf("redirect to %v: %w", req.URL.Redacted(), err)
+		}
+	}
+
+	prev := via[len(via)-1]
+	crossHost := req.URL.Host != prev.URL.Host
+	if crossHost {
+		for name := range req.Header {
+			if isCredentialHeader(name) {
+				req.Header.Del(name)
+			}
+		}
+	}
+
+	attrs := []any{"from", prev.URL.Redacted(), "to", req.URL.Redacted(), "cross_host", crossHost}
+	if req.Response != nil {
+		attrs = append(attrs, "status", req.Response.StatusCode)
+	}
+	slog.DebugContext(req.Context(), "Following upstream redirect", attrs...)
+
+	return nil
+}
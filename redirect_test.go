@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newRedirectRequest returns a request for rawURL, as checkRedirect receives
+// it after following redirects from the URLs in via.
+func newRedirectRequest(t *testing.T, rawURL string, via ...string) (*http.Request, []*http.Request) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reqs []*http.Request
+	for _, v := range via {
+		prev, err := http.NewRequest(http.MethodGet, v, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reqs = append(reqs, prev)
+	}
+	return req, reqs
+}
+
+func TestCheckRedirectLimit(t *testing.T) {
+	for n := 1; n <= maxRedirects+1; n++ {
+		t.Run(fmt.Sprintf("%d redirects", n), func(t *testing.T) {
+			var via []string
+			for i := range n - 1 {
+				via = append(via, fmt.Sprintf("https://example.com/%d", i))
+			}
+			via = append([]string{"https://example.com/start"}, via...)
+			req, reqs := newRedirectRequest(t, "https://example.com/end", via...)
+
+			err := checkRedirect(req, reqs)
+			if n <= maxRedirects && err != nil {
+				t.Errorf("redirect %d: unexpected error: %v", n, err)
+			}
+			if n > maxRedirects && (err == nil || !strings.Contains(err.Error(), "stopped after")) {
+				t.Errorf("redirect %d: error = %v, want the redirect limit", n, err)
+			}
+		})
+	}
+}
+
+func TestCheckRedirectLoop(t *testing.T) {
+	req, via := newRedirectRequest(t, "https://example.com/a", "https://example.com/a", "https://example.com/b")
+
+	err := checkRedirect(req, via)
+	if err == nil || !strings.Contains(err.Error(), "redirect loop") {
+		t.Errorf("error = %v, want a redirect loop", err)
+	}
+}
+
+func TestCheckRedirectCredentials(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		wantStrip bool
+	}{
+		{"same host", "https://example.com/a", "https://example.com/b", false},
+		{"other host", "https://example.com/a", "https://other.example/b", true},
+		{"other port", "https://example.com/a", "https://example.com:8443/b", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, via := newRedirectRequest(t, tt.to, tt.from)
+			for _, name := range []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "Accept"} {
+				req.Header.Set(name, "value")
+			}
+
+			if err := checkRedirect(req, via); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, name := range []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"} {
+				if stripped := req.Header.Get(name) == ""; stripped != tt.wantStrip {
+					t.Errorf("%v stripped = %v, want %v", name, stripped, tt.wantStrip)
+				}
+			}
+			if req.Header.Get("Accept") == "" {
+				t.Error("Accept header was stripped")
+			}
+		})
+	}
+}
+
+func TestRedirectToOtherHostDropsCredentials(t *testing.T) {
+	var got http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		fixture{body: `[]`}.serve(w)
+	}))
+	t.Cleanup(other.Close)
+
+	newTestServer(t, http.RedirectHandler(other.URL+"/apis", http.StatusFound))
+
+	req, err := http.NewRequest(http.MethodGet, apiBaseURL+"/apis", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got == nil {
+		t.Fatal("redirect wasn't followed")
+	}
+	if got.Get("Authorization") != "" || got.Get("X-Api-Key") != "" {
+		t.Errorf("credentials forwarded to other host: %v", got)
+	}
+	if got.Get("Accept") != "application/json" {
+		t.Errorf("Accept = %q, want it forwarded", got.Get("Accept"))
+	}
+}
+
+func TestRedirectLimitOverHTTP(t *testing.T) {
+	var requests int
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		http.Redirect(w, r, fmt.Sprintf("/apis/%d", requests), http.StatusFound)
+	}))
+
+	resp, err := httpClient.Get(apiBaseURL + "/apis")
+	if err == nil {
+		resp.Body.Close()
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "stopped after") {
+		t.Errorf("error = %v, want the redirect limit", err)
+	}
+	if requests != maxRedirects+1 {
+		t.Errorf("got %d requests, want %d", requests, maxRedirects+1)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// GetRepositoryParams represents the parameters for the getRepository tool.
+// The `id` parameter is required.
+type GetRepositoryParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the repository."`
+}
+
+// createGetRepositoryTool creates a tool for getting a repository by ID.
+func createGetRepositoryTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetRepositoryParams]{
+		Name: "get_repository",
+		Description: "Get a specific repository by ID from the Developer Overheid API. The result includes the " +
+			"derived fields `source_host` (e.g. github.com) and `web_url`, when the repository URL can be parsed.",
+		HandleFunc: func(ctx context.Context, params GetRepositoryParams) *mcp.CallToolResult {
+			id, err := validateID(params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Invalid id: %v", err)
+			}
+
+			repo, err := fetchRepository(ctx, id)
+			if errors.Is(err, errNoContent) {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{
+						mcp.TextContent{
+							Text: fmt.Sprintf("Repository with ID %v exists, but upstream returned no content", id),
+						},
+					},
+				}
+			}
+			if err != nil {
+				return newFetchErrorResult(ctx, "repository", err)
+			}
+
+			if host, webURL, err := parseSourceURL(recordString(repo, "url", "repository_url", "source_url")); err == nil {
+				repo["source_host"] = host
+				repo["web_url"] = webURL
+			}
+
+			result, err := marshalResult(normalizeValue(repo), prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// parseSourceURL derives the normalized source host (e.g. "github.com") and a
+// browsable HTTPS URL from a repository URL. Both HTTP(S) URLs and SCP-like SSH
+// URLs (`git@host:owner/repo.git`) are supported.
+func parseSourceURL(rawURL string) (string, string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", "", errors.New("empty URL")
+	}
+
+	// Rewrite SCP-like syntax to an SSH URL so it can be parsed.
+	if !strings.Contains(rawURL, "://") {
+		if at, colon := strings.Index(rawURL, "@"), strings.Index(rawURL, ":"); at >= 0 && colon > at {
+			rawURL = "ssh://" + rawURL[:colon] + "/" + rawURL[colon+1:]
+		}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	switch u.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		return "", "", fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if host == "" {
+		return "", "", errors.New("URL has no host")
+	}
+
+	// The port of an SSH URL doesn't apply to the web interface.
+	webHost := host
+	if port := u.Port(); port != "" && (u.Scheme == "http" || u.Scheme == "https") {
+		webHost += ":" + port
+	}
+
+	path := strings.TrimSuffix(strings.TrimRight(u.Path, "/"), ".git")
+	webURL := url.URL{Scheme: "https", Host: webHost, Path: path}
+
+	return host, webURL.String(), nil
+}
+
+// fetchRepository fetches a single repository record by ID and decodes it into
+// a map.
+func fetchRepository(ctx context.Context, id string) (map[string]any, error) {
+	id, err := validateID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	repoURL, err := joinURL(apiBaseURL, "repositories", url.PathEscape(id))
+	if err != nil {
+		return nil, fmt.Errorf("error building URL: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, repoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := doWithRetry(ctx, httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, statusError{code: resp.StatusCode, err: fmt.Errorf("repository with ID %v not found", id)}
+	}
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+
+	body, err := decodeJSONBody(resp, nil)
+	if errors.Is(err, errNoContent) {
+		return nil, fmt.Errorf("repository with ID %v exists, but %w", id, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	var repo map[string]any
+	if err := json.Unmarshal(body, &repo); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return repo, nil
+}
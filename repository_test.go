@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestGetRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantHost   string
+		wantWebURL string
+	}{
+		{"GitHub", "https://github.com/owner/repo.git", "github.com", "https://github.com/owner/repo"},
+		{"GitLab SSH", "git@gitlab.com:group/sub/project.git", "gitlab.com", "https://gitlab.com/group/sub/project"},
+		{"malformed", "not a URL", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record, _ := json.Marshal(map[string]string{"id": "r1", "url": tt.url})
+			newFixtureServer(t, map[string]fixture{
+				"/repositories/r1": {body: string(record)},
+			})
+
+			res := callTool(t, createGetRepositoryTool(), `{"id":"r1"}`)
+
+			var got map[string]any
+			decodeResult(t, res, &got)
+			if got["url"] != tt.url {
+				t.Errorf("url = %v, want the upstream URL", got["url"])
+			}
+			if host, _ := got["source_host"].(string); host != tt.wantHost {
+				t.Errorf("source_host = %q, want %q", host, tt.wantHost)
+			}
+			if webURL, _ := got["web_url"].(string); webURL != tt.wantWebURL {
+				t.Errorf("web_url = %q, want %q", webURL, tt.wantWebURL)
+			}
+		})
+	}
+}
+
+func TestGetRepositoryErrors(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		newFixtureServer(t, nil)
+
+		res := callTool(t, createGetRepositoryTool(), `{"id":"missing"}`)
+		expectError(t, res, "repository with ID missing not found")
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/repositories/r1": {status: http.StatusNoContent},
+		})
+
+		res := callTool(t, createGetRepositoryTool(), `{"id":"r1"}`)
+		if res.IsError {
+			t.Errorf("unexpected error result: %v", resultText(t, res))
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/repositories/r1": {body: `{"id":"r1"}`},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		res, err := createGetRepositoryTool().HandleFunc(ctx, json.RawMessage(`{"id":"r1"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectError(t, res, "tool call aborted")
+	})
+}
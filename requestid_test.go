@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestRequestIDs(t *testing.T) {
+	var upstreamIDs []string
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamIDs = append(upstreamIDs, r.Header.Get(requestIDHeader))
+		fixture{body: `[]`}.serve(w)
+	}))
+
+	logger, buf := newJSONLogger(t)
+	oldLogger := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(oldLogger) })
+	slog.SetDefault(logger)
+	httpClient = newUpstreamClient(http.DefaultTransport.(*http.Transport).Clone(), logger)
+
+	tool := createListAPIsTool(httpClient, apiBaseURL)
+	callTool(t, tool, `{}`)
+	callTool(t, tool, `{}`)
+
+	if len(upstreamIDs) != 2 || upstreamIDs[0] == "" || upstreamIDs[1] == "" {
+		t.Fatalf("upstream got request IDs %q, want one per call", upstreamIDs)
+	}
+	if upstreamIDs[0] == upstreamIDs[1] {
+		t.Errorf("both calls got request ID %q, want a new ID per call", upstreamIDs[0])
+	}
+
+	// Every record logged during a call carries the ID sent upstream.
+	loggedIDs := make(map[string][]string)
+	for _, rec := range logRecords(t, buf) {
+		id, _ := rec["request_id"].(string)
+		if id == "" {
+			t.Errorf("record %v has no request ID", rec)
+			continue
+		}
+		loggedIDs[id] = append(loggedIDs[id], rec["msg"].(string))
+	}
+	for _, id := range upstreamIDs {
+		msgs := loggedIDs[id]
+		if !slices.Contains(msgs, "Upstream request") || !slices.Contains(msgs, "Tool call finished") {
+			t.Errorf("records with request ID %v = %q, want the upstream request and the tool call", id, msgs)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+	"time"
+)
+
+// Default duration for which upstream responses are cached.
+const defaultCacheTTL = 60 * time.Second
+
+// Limits for the response cache: the maximum number of entries, and the
+// maximum size of a single cached response.
+const (
+	maxCacheEntries   = 1000
+	maxCachedResponse = 2 << 20
+)
+
+// cache is a concurrency-safe in-memory cache with per-entry expiry.
+type cache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	value     []byte
+	etag      string
+	expiresAt time.Time
+}
+
+// newCache returns an empty cache.
+func newCache() *cache {
+	return &cache{entries: make(map[string]cacheEntry)}
+}
+
+// Get returns the value stored for key, if present and not expired. Expired
+// entries are removed, unless they have an ETag to revalidate them with (see
+// Stale).
+func (c *cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(entry.expiresAt) {
+		if entry.etag == "" {
+			delete(c.entries, key)
+		}
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Stale returns the value and ETag stored for key, if present, expired, and
+// stored with an ETag.
+func (c *cache) Stale(key string) ([]byte, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || entry.etag == "" || time.Now().Before(entry.expiresAt) {
+		return nil, "", false
+	}
+	return entry.value, entry.etag, true
+}
+
+// Set stores value for key, along with its ETag (if any), expiring after ttl.
+// When the cache is full, expired entries are evicted first; if it's still
+// full, value isn't stored.
+func (c *cache) Set(key string, value []byte, etag string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCacheEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCacheEntries {
+			return
+		}
+	}
+
+	c.entries[key] = cacheEntry{value: value, etag: etag, expiresAt: now.Add(ttl)}
+}
+
+// noCacheContextKey is the context key for bypassing the response cache.
+type noCacheContextKey struct{}
+
+// contextWithoutCache returns a copy of ctx for which upstream requests bypass
+// the response cache (their responses are still stored).
+func contextWithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheContextKey{}, true)
+}
+
+// cachingTransport is an http.RoundTripper that caches successful (2xx)
+// responses to GET requests, keyed by the full request URL and the requested
+// language.
+//
+// Once a cached response with an ETag expires, it's revalidated rather than
+// refetched: the request is sent with If-None-Match, and a 304 Not Modified
+// response renews the cached response, which is served instead.
+type cachingTransport struct {
+	next  http.RoundTripper
+	cache *cache
+	ttl   time.Duration
+}
+
+func (t cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	key := req.URL.String() + "\x00" + req.Header.Get("Accept-Language")
+
+	if bypass, _ := req.Context().Value(noCacheContextKey{}).(bool); !bypass {
+		if data, ok := t.cache.Get(key); ok {
+			if resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req); err == nil {
+				return resp, nil
+			}
+		}
+	}
+
+	stale, etag, revalidate := t.cache.Stale(key)
+	if revalidate && req.Header.Get("If-None-Match") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	} else {
+		revalidate = false
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if revalidate && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		t.cache.Set(key, stale, etag, t.ttl)
+		return http.ReadResponse(bufio.NewReader(bytes.NewReader(stale)), req)
+	}
+	if !isSuccessStatus(resp.StatusCode) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedResponse+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedResponse {
+		// Too large to cache: pass the response through unbuffered.
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if data, err := httputil.DumpResponse(resp, true); err == nil {
+		t.cache.Set(key, data, resp.Header.Get("ETag"), t.ttl)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return resp, nil
+}
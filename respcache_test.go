@@ -0,0 +1,156 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newETagServer starts a server for a resource with the given ETag, which
+// answers a matching If-None-Match with 304 Not Modified. It returns the
+// server and its request counter.
+func newETagServer(t *testing.T, etag, body string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &requests
+}
+
+func TestCachingTransport(t *testing.T) {
+	get := func(t *testing.T, rt http.RoundTripper, url string) (int, string) {
+		t.Helper()
+
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("fresh response served from cache", func(t *testing.T) {
+		srv, requests := newETagServer(t, `"v1"`, `{"id":"a"}`)
+		rt := cachingTransport{next: http.DefaultTransport, cache: newCache(), ttl: time.Hour}
+
+		for range 3 {
+			if status, body := get(t, rt, srv.URL); status != http.StatusOK || body != `{"id":"a"}` {
+				t.Errorf("got %d %q, want the cached 200 response", status, body)
+			}
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("got %d upstream requests, want 1", n)
+		}
+	})
+
+	t.Run("expired response revalidated with ETag", func(t *testing.T) {
+		srv, requests := newETagServer(t, `"v1"`, `{"id":"a"}`)
+		// Entries expire immediately, so every request revalidates.
+		rt := cachingTransport{next: http.DefaultTransport, cache: newCache(), ttl: 0}
+
+		get(t, rt, srv.URL)
+		for i := range 2 {
+			status, body := get(t, rt, srv.URL)
+			if status != http.StatusOK || body != `{"id":"a"}` {
+				t.Errorf("got %d %q after a 304, want the cached 200 response", status, body)
+			}
+			if n := requests.Load(); n != int64(i+2) {
+				t.Errorf("got %d upstream requests, want %d", n, i+2)
+			}
+		}
+	})
+
+	t.Run("changed response replaces the cached one", func(t *testing.T) {
+		var changed atomic.Bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			etag, body := `"v1"`, `{"id":"a"}`
+			if changed.Load() {
+				etag, body = `"v2"`, `{"id":"b"}`
+			}
+			w.Header().Set("ETag", etag)
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		c := newCache()
+		rt := cachingTransport{next: http.DefaultTransport, cache: c, ttl: 0}
+
+		get(t, rt, srv.URL)
+		changed.Store(true)
+		if status, body := get(t, rt, srv.URL); status != http.StatusOK || body != `{"id":"b"}` {
+			t.Errorf("got %d %q, want the new response", status, body)
+		}
+		if _, etag, ok := c.Stale(srv.URL + "\x00"); !ok || etag != `"v2"` {
+			t.Errorf("cached ETag = %q, want the new ETag", etag)
+		}
+	})
+
+	t.Run("error responses aren't cached", func(t *testing.T) {
+		var requests atomic.Int64
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		t.Cleanup(srv.Close)
+		rt := cachingTransport{next: http.DefaultTransport, cache: newCache(), ttl: time.Hour}
+
+		get(t, rt, srv.URL)
+		get(t, rt, srv.URL)
+		if n := requests.Load(); n != 2 {
+			t.Errorf("got %d upstream requests, want 2", n)
+		}
+	})
+}
+
+func TestToolCallsUseResponseCache(t *testing.T) {
+	_, requests := newFixtureServer(t, map[string]fixture{
+		"/apis/a": {body: `{"id":"a"}`},
+		"/apis/b": {body: `{"id":"b"}`},
+	})
+	httpClient.Transport = cachingTransport{next: httpClient.Transport, cache: newCache(), ttl: time.Minute}
+	tool := createGetAPITool(httpClient, apiBaseURL)
+
+	for range 2 {
+		var got struct {
+			ID string `json:"id"`
+		}
+		decodeResult(t, callTool(t, tool, `{"id":"a"}`), &got)
+		if got.ID != "a" {
+			t.Errorf("got API %q, want a", got.ID)
+		}
+	}
+	if len(*requests) != 1 {
+		t.Errorf("got %d upstream requests for identical calls within the TTL, want 1", len(*requests))
+	}
+
+	// Other IDs and languages are cached separately.
+	callTool(t, tool, `{"id":"b"}`)
+	callTool(t, tool, `{"id":"a","lang":"en"}`)
+	if len(*requests) != 3 {
+		t.Errorf("got %d upstream requests, want 3", len(*requests))
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Response headers included in tool results with -include-headers, on top of
+// those added with -include-header: rate limiting, caching, pagination and
+// deprecation notices.
+var defaultIncludedHeaders = []string{
+	"Cache-Control",
+	"Deprecation",
+	"Etag",
+	"Retry-After",
+	"Sunset",
+	"Warning",
+	"X-Ratelimit-Limit",
+	"X-Ratelimit-Remaining",
+	"X-Ratelimit-Reset",
+	"X-Total-Count",
+}
+
+// headerNamesFlag is a repeatable flag of header names.
+type headerNamesFlag struct {
+	names []string
+}
+
+// String returns the header names, comma-separated.
+func (f *headerNamesFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.names, ", ")
+}
+
+// Set adds a header name to the flag's names.
+func (f *headerNamesFlag) Set(s string) error {
+	name := strings.TrimSpace(s)
+	if name == "" || strings.ContainsAny(name, " \t:") {
+		return fmt.Errorf("invalid header name %q", s)
+	}
+	f.names = append(f.names, textproto.CanonicalMIMEHeaderKey(name))
+	return nil
+}
+
+// responseHeadersContextKey is the context key for the response header
+// recorder of a tool call.
+type responseHeadersContextKey struct{}
+
+// responseHeaderRecorder records the included headers of the upstream
+// responses of a tool call. For a header set by multiple responses, the value
+// of the last response is kept.
+type responseHeaderRecorder struct {
+	mu      sync.Mutex
+	headers map[string]string
+}
+
+func (r *responseHeaderRecorder) record(header http.Header, names []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, name := range names {
+		values := header.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if r.headers == nil {
+			r.headers = make(map[string]string)
+		}
+		r.headers[name] = strings.Join(values, ", ")
+	}
+}
+
+// responseHeaderTransport is an http.RoundTripper that records the headers
+// named in names of responses from host with the recorder of the tool call,
+// if any.
+type responseHeaderTransport struct {
+	next  http.RoundTripper
+	host  string
+	names []string
+}
+
+func (t responseHeaderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || req.URL.Host != t.host {
+		return resp, err
+	}
+
+	if rec, ok := req.Context().Value(responseHeadersContextKey{}).(*responseHeaderRecorder); ok {
+		rec.record(resp.Header, t.names)
+	}
+
+	return resp, nil
+}
+
+// handleWithResponseHeaders calls a tool handler, and adds the included
+// headers of its upstream responses to the result, in a `_headers` field. For
+// results that aren't a JSON object (e.g. NDJSON or OpenAPI documents), the
+// field is added as a separate text content. Error results are returned as-is.
+func handleWithResponseHeaders[T any](ctx context.Context, handle func(context.Context, T) *mcp.CallToolResult, params T) *mcp.CallToolResult {
+	rec := &responseHeaderRecorder{}
+	result := handle(context.WithValue(ctx, responseHeadersContextKey{}, rec), params)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if result == nil || result.IsError || len(rec.headers) == 0 || len(result.Content) == 0 {
+		return result
+	}
+
+	// NDJSON results end with a newline, unlike JSON results, so that a
+	// single NDJSON record isn't taken for a JSON object.
+	if text, ok := result.Content[0].(mcp.TextContent); ok && !strings.HasSuffix(text.Text, "\n") &&
+		bytes.HasPrefix(bytes.TrimSpace([]byte(text.Text)), []byte("{")) {
+		if obj, err := prependJSONField([]byte(text.Text), "_headers", rec.headers); err == nil {
+			text.Text = string(obj)
+			result.Content[0] = text
+			return result
+		}
+	}
+
+	headers, err := json.Marshal(map[string]any{"_headers": rec.headers})
+	if err != nil {
+		return result
+	}
+	result.Content = append(result.Content, mcp.TextContent{
+		Text: string(headers),
+	})
+
+	return result
+}
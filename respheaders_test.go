@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// setIncludeHeaders enables -include-headers for the duration of the test, and
+// records the default included headers (plus names) of responses from the
+// test server started by newTestServer.
+func setIncludeHeaders(t *testing.T, names ...string) {
+	t.Helper()
+
+	old := includeHeaders
+	t.Cleanup(func() { includeHeaders = old })
+	includeHeaders = true
+
+	u, err := url.Parse(apiBaseURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient = &http.Client{
+		Transport: responseHeaderTransport{
+			next:  httpClient.Transport,
+			host:  u.Host,
+			names: append(defaultIncludedHeaders[:len(defaultIncludedHeaders):len(defaultIncludedHeaders)], names...),
+		},
+		CheckRedirect: httpClient.CheckRedirect,
+	}
+}
+
+func TestIncludeHeaders(t *testing.T) {
+	header := http.Header{
+		"Link":                  {`</apis?page=2>; rel="next"`},
+		"X-Total-Count":         {"42"},
+		"X-Ratelimit-Remaining": {"99"},
+		"X-Request-Id":          {"abc"},
+		"X-Internal":            {"secret"},
+	}
+
+	t.Run("JSON object result", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {header: header, body: `[{"id":"a"}]`},
+		})
+		setIncludeHeaders(t, "X-Request-Id")
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+
+		text := resultText(t, res)
+		if !strings.HasPrefix(text, `{"_headers":`) {
+			t.Errorf("result %q doesn't start with the _headers field", text)
+		}
+		var got struct {
+			Headers map[string]string `json:"_headers"`
+			APIs    []any             `json:"apis"`
+		}
+		decodeResult(t, res, &got)
+
+		want := map[string]string{"X-Total-Count": "42", "X-Ratelimit-Remaining": "99", "X-Request-Id": "abc"}
+		if len(got.Headers) != len(want) {
+			t.Errorf("_headers = %v, want %v", got.Headers, want)
+		}
+		for name, value := range want {
+			if got.Headers[name] != value {
+				t.Errorf("_headers[%v] = %q, want %q", name, got.Headers[name], value)
+			}
+		}
+		if len(got.APIs) != 1 {
+			t.Errorf("got %d APIs, want 1", len(got.APIs))
+		}
+	})
+
+	t.Run("NDJSON result", func(t *testing.T) {
+		setOutputFormat(t, outputFormatNDJSON)
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {header: header, body: `[{"id":"a"}]`},
+		})
+		setIncludeHeaders(t)
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+
+		if first := res.Content[0].(mcp.TextContent).Text; first != "{\"id\":\"a\"}\n" {
+			t.Errorf("records = %q, want them unchanged", first)
+		}
+		var got struct {
+			Headers map[string]string `json:"_headers"`
+		}
+		decodeResult(t, res, &got)
+		if got.Headers["X-Total-Count"] != "42" {
+			t.Errorf("_headers = %v, want X-Total-Count", got.Headers)
+		}
+	})
+
+	t.Run("error result", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusInternalServerError, header: header, body: `{"message":"boom"}`},
+		})
+		setIncludeHeaders(t)
+
+		res := callTool(t, createListAPIsTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "boom")
+		if strings.Contains(resultText(t, res), "_headers") {
+			t.Errorf("error result %q has _headers", resultText(t, res))
+		}
+	})
+}
+
+func TestResponseHeaderTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Warning", `299 - "first"`)
+		w.Header().Add("Warning", `299 - "second"`)
+		w.Header().Set("Etag", `"v1"`)
+		w.Header().Set("X-Other", "x")
+	}))
+	t.Cleanup(srv.Close)
+	u, _ := url.Parse(srv.URL)
+
+	get := func(t *testing.T, host string) map[string]string {
+		t.Helper()
+
+		client := &http.Client{Transport: responseHeaderTransport{
+			next:  srv.Client().Transport,
+			host:  host,
+			names: []string{"Warning", "Etag", "Sunset"},
+		}}
+		rec := &responseHeaderRecorder{}
+		ctx := context.WithValue(context.Background(), responseHeadersContextKey{}, rec)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return rec.headers
+	}
+
+	t.Run("API host", func(t *testing.T) {
+		got := get(t, u.Host)
+		if len(got) != 2 || got["Warning"] != `299 - "first", 299 - "second"` || got["Etag"] != `"v1"` {
+			t.Errorf("recorded %v, want the joined Warning and Etag headers only", got)
+		}
+	})
+
+	t.Run("other host", func(t *testing.T) {
+		if got := get(t, "example.com"); len(got) != 0 {
+			t.Errorf("recorded %v for another host, want none", got)
+		}
+	})
+}
+
+func TestHeaderNamesFlag(t *testing.T) {
+	var f headerNamesFlag
+	for _, name := range []string{"x-request-id", " X-Trace "} {
+		if err := f.Set(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := f.String(); got != "X-Request-Id, X-Trace" {
+		t.Errorf("names = %q, want canonicalized names", got)
+	}
+
+	for _, name := range []string{"", " ", "X-A: b", "X A"} {
+		if err := f.Set(name); err == nil {
+			t.Errorf("Set(%q) succeeded, want error", name)
+		}
+	}
+}
+
+func TestHandleWithResponseHeadersNoHeaders(t *testing.T) {
+	handle := func(ctx context.Context, _ struct{}) *mcp.CallToolResult {
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Text: `{"a":1}`}}}
+	}
+
+	res := handleWithResponseHeaders(context.Background(), handle, struct{}{})
+	if len(res.Content) != 1 || res.Content[0].(mcp.TextContent).Text != `{"a":1}` {
+		t.Errorf("result = %v, want it unchanged", res.Content)
+	}
+}
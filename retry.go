@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Default number of retries for transient upstream failures.
+const defaultMaxRetries = 3
+
+// Delays for retrying upstream requests: the initial backoff, which doubles
+// with each retry, and the maximum delay (also applied to `Retry-After`).
+const (
+	retryBaseDelay = 250 * time.Millisecond
+	retryMaxDelay  = 10 * time.Second
+)
+
+// doWithRetry sends a request, retrying on connection errors and on 5xx and
+// 429 responses, up to the configured number of retries. Retries back off
+// exponentially with jitter, unless the response has a `Retry-After` header.
+// Only requests without a body can be retried. Connection errors are returned
+// in the errNetwork category.
+func doWithRetry(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Do(req.Clone(ctx))
+		if attempt >= maxRetries || req.Body != nil || !isRetryable(resp, err) {
+			if err != nil && !isContextError(err) && !errors.Is(err, errDryRun) {
+				err = withCategory(errNetwork, err)
+			}
+			return resp, err
+		}
+
+		delay := backoffDelay(attempt)
+		if resp != nil {
+			if d, ok := retryAfter(resp); ok {
+				delay = d
+			}
+			// Drain the body, so the connection can be reused.
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable reports whether a request that resulted in resp or err may
+// succeed when retried.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Errors caused by the request's context are final, as are
+		// requests that weren't sent in dry-run mode.
+		return !isContextError(err) && !errors.Is(err, errDryRun)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// isContextError reports whether err was caused by a canceled or expired
+// context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// backoffDelay returns the delay before retry number attempt+1: exponential
+// backoff, capped at retryMaxDelay, with up to half of it as random jitter.
+func backoffDelay(attempt int) time.Duration {
+	d := min(retryBaseDelay<<attempt, retryMaxDelay)
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter returns the delay requested by the `Retry-After` header of a 429
+// or 503 response, capped at retryMaxDelay. Both delay-seconds and HTTP dates
+// are supported.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	} else {
+		return 0, false
+	}
+
+	return min(max(d, 0), retryMaxDelay), true
+}
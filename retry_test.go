@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer starts a test server (see newTestServer) that responds with
+// failure to the first failures requests, and with 200 OK after that. Retries
+// are enabled, up to retries.
+func newFlakyServer(t *testing.T, failures int, failure fixture, retries int) *atomic.Int64 {
+	t.Helper()
+
+	var requests atomic.Int64
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= int64(failures) {
+			failure.serve(w)
+			return
+		}
+		fixture{body: `[]`}.serve(w)
+	}))
+	maxRetries = retries
+
+	return &requests
+}
+
+func getWithRetry(t *testing.T) (*http.Response, error) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, apiBaseURL+"/apis", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := doWithRetry(t.Context(), httpClient, req)
+	if err == nil {
+		t.Cleanup(func() { resp.Body.Close() })
+	}
+	return resp, err
+}
+
+func TestDoWithRetry(t *testing.T) {
+	t.Run("succeeds after transient failures", func(t *testing.T) {
+		requests := newFlakyServer(t, 2, fixture{status: http.StatusBadGateway}, 3)
+
+		resp, err := getWithRetry(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %v, want 200", resp.StatusCode)
+		}
+		if n := requests.Load(); n != 3 {
+			t.Errorf("got %d requests, want 3", n)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		requests := newFlakyServer(t, 10, fixture{status: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"0"}}}, 2)
+
+		resp, err := getWithRetry(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("status = %v, want 503", resp.StatusCode)
+		}
+		if n := requests.Load(); n != 3 {
+			t.Errorf("got %d requests, want 3", n)
+		}
+	})
+
+	t.Run("honors Retry-After", func(t *testing.T) {
+		requests := newFlakyServer(t, 1, fixture{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"0"}}}, 3)
+
+		start := time.Now()
+		resp, err := getWithRetry(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusOK || requests.Load() != 2 {
+			t.Errorf("got status %v after %d requests, want 200 after 2", resp.StatusCode, requests.Load())
+		}
+		// Without Retry-After, the first retry is delayed by at least half
+		// of retryBaseDelay.
+		if elapsed := time.Since(start); elapsed >= retryBaseDelay/2 {
+			t.Errorf("retried after %v, want Retry-After: 0 to retry immediately", elapsed)
+		}
+	})
+
+	t.Run("doesn't retry client errors", func(t *testing.T) {
+		requests := newFlakyServer(t, 1, fixture{status: http.StatusNotFound}, 3)
+
+		resp, err := getWithRetry(t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("status = %v, want 404", resp.StatusCode)
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("got %d requests, want 1", n)
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"seconds", http.StatusTooManyRequests, "2", 2 * time.Second, true},
+		{"capped", http.StatusServiceUnavailable, "3600", retryMaxDelay, true},
+		{"date in the past", http.StatusServiceUnavailable, "Wed, 21 Oct 2015 07:28:00 GMT", 0, true},
+		{"invalid", http.StatusTooManyRequests, "soon", 0, false},
+		{"missing", http.StatusTooManyRequests, "", 0, false},
+		{"other status", http.StatusInternalServerError, "2", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.value != "" {
+				resp.Header.Set("Retry-After", tt.value)
+			}
+			got, ok := retryAfter(resp)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("retryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := range 8 {
+		d := min(retryBaseDelay<<attempt, retryMaxDelay)
+		for range 20 {
+			if got := backoffDelay(attempt); got < d/2 || got > d {
+				t.Errorf("backoffDelay(%d) = %v, want between %v and %v", attempt, got, d/2, d)
+			}
+		}
+	}
+}
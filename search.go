@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// SearchAPIsParams represents the parameters for the searchAPIs tool.
+// The `query` parameter is required. The `page` parameter is optional; pages
+// are numbered from 1, and an omitted page (or page 0) means the first page.
+type SearchAPIsParams struct {
+	Query string `json:"query" jsonschema:"required" jsonschema_description:"Free-text search query."`
+	Page  *int   `json:"page,omitempty" jsonschema_description:"Page number, starting at 1. Defaults to 1 (also for 0)."`
+}
+
+// createSearchAPIsTool creates a tool for searching APIs with a query string.
+func createSearchAPIsTool(client *http.Client, baseURL string) mcp.Tool {
+	return createTool(mcp.ToolDef[SearchAPIsParams]{
+		Name: "search_apis",
+		Description: "Search APIs from the Developer Overheid API with a free-text query, which is forwarded to the " +
+			"upstream search. Pages are numbered from 1 (the default).",
+		HandleFunc: func(ctx context.Context, params SearchAPIsParams) *mcp.CallToolResult {
+			query := strings.TrimSpace(params.Query)
+			if query == "" {
+				return newToolCallErrorResult("Invalid query: must not be empty")
+			}
+
+			page, err := resolvePage(params.Page)
+			if err != nil {
+				return newToolCallErrorResult("Invalid page: %v", err)
+			}
+
+			q := pageQuery(page)
+			q.Set("q", query)
+
+			apiURL, err := buildURL(baseURL, q, "apis")
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
+			}
+
+			lp, err := fetchList(ctx, client, apiURL, page)
+			if err != nil {
+				return newFetchErrorResult(ctx, "APIs", err)
+			}
+
+			apis, err := normalizeRecords(lp.Body)
+			if err != nil {
+				return newToolCallErrorResult("Error normalizing response: %v", err)
+			}
+
+			response := ListAPIsResponse{
+				APIs:       apis,
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			}
+
+			return newListResult(response, apis, ListPagination{
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			})
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchAPIs(t *testing.T) {
+	t.Run("success with pagination", func(t *testing.T) {
+		_, requests := newFixtureServer(t, map[string]fixture{
+			"/apis": listPageFixture("/apis", `[{"id":"a"}]`, 3, 1, 5, 42),
+		})
+
+		res := callTool(t, createSearchAPIsTool(httpClient, apiBaseURL), `{"query":" kadaster ","page":2}`)
+
+		var got struct {
+			APIs []struct {
+				ID string `json:"id"`
+			} `json:"apis"`
+			listPageResult
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.APIs) != 1 || got.APIs[0].ID != "a" {
+			t.Errorf("apis = %+v, want a", got.APIs)
+		}
+		want := listPageResult{NextPage: 3, PrevPage: 1, LastPage: 5, TotalCount: 42}
+		if got.listPageResult != want {
+			t.Errorf("pagination = %+v, want %+v", got.listPageResult, want)
+		}
+
+		if len(*requests) != 1 {
+			t.Fatalf("got %d upstream requests, want 1", len(*requests))
+		}
+		if q := (*requests)[0].URL.Query(); q.Get("q") != "kadaster" || q.Get("page") != "2" {
+			t.Errorf("requested %v, want q=kadaster and page=2", q)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		res := callTool(t, createSearchAPIsTool(httpClient, apiBaseURL), `{"query":"  "}`)
+		expectError(t, res, "must not be empty")
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusInternalServerError, body: `{"message":"search unavailable"}`},
+		})
+
+		res := callTool(t, createSearchAPIsTool(httpClient, apiBaseURL), `{"query":"kadaster"}`)
+		expectError(t, res, "[upstream]")
+		expectError(t, res, "search unavailable")
+	})
+}
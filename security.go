@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Maximum number of OpenAPI documents inspected per listAPIsBySecurity call.
+const maxSecuritySpecs = 100
+
+// Security scheme kinds that can be filtered on.
+var securitySchemeKinds = []string{"oauth2", "apikey", "http-bearer", "http-basic", "openidconnect", "mutualtls"}
+
+// ListAPIsBySecurityParams represents the parameters for the listAPIsBySecurity tool.
+// The `scheme` parameter is required.
+type ListAPIsBySecurityParams struct {
+	Scheme string `json:"scheme" jsonschema:"required" jsonschema_description:"Security scheme: oauth2, apikey, http-bearer, http-basic, openidconnect or mutualtls."`
+}
+
+// ListAPIsBySecurityResponse represents the response from the listAPIsBySecurity tool.
+type ListAPIsBySecurityResponse struct {
+	APIs     []SecurityMatch  `json:"apis"`
+	Coverage SecurityCoverage `json:"coverage"`
+}
+
+// SecurityMatch represents an API declaring the requested security scheme.
+type SecurityMatch struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Schemes []string `json:"schemes"`
+}
+
+// SecurityCoverage reports how much of the catalog was inspected.
+type SecurityCoverage struct {
+	PagesScanned int  `json:"pages_scanned"`
+	APIsScanned  int  `json:"apis_scanned"`
+	SpecsChecked int  `json:"specs_checked"`
+	SpecsMissing int  `json:"specs_missing"`
+	SpecsFailed  int  `json:"specs_failed"`
+	Truncated    bool `json:"truncated,omitempty"`
+}
+
+// createListAPIsBySecurityTool creates a tool for listing APIs by the security
+// schemes declared in their OpenAPI specification.
+func createListAPIsBySecurityTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ListAPIsBySecurityParams]{
+		Name: "list_apis_by_security",
+		Description: "List APIs whose OpenAPI specification declares a given security scheme: one of " +
+			strings.Join(securitySchemeKinds, ", ") + ". Specifications are fetched per API, so the work is bounded; " +
+			"the response reports the coverage of the catalog.",
+		HandleFunc: func(ctx context.Context, params ListAPIsBySecurityParams) *mcp.CallToolResult {
+			kind := strings.ToLower(strings.TrimSpace(params.Scheme))
+			if !slices.Contains(securitySchemeKinds, kind) {
+				return newToolCallErrorResult("Unsupported scheme %q, must be one of: %v", params.Scheme, strings.Join(securitySchemeKinds, ", "))
+			}
+
+			response := ListAPIsBySecurityResponse{
+				APIs: []SecurityMatch{},
+			}
+			cov := &response.Coverage
+
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				if cov.SpecsChecked+cov.SpecsFailed == maxSecuritySpecs {
+					cov.Truncated = true
+					return errStopWalk
+				}
+				cov.APIsScanned++
+
+				var rec map[string]any
+				if err := json.Unmarshal(item, &rec); err != nil {
+					return err
+				}
+
+				specURL := specificationURL(rec)
+				if specURL == "" {
+					cov.SpecsMissing++
+					return nil
+				}
+
+				spec, err := fetchSpecCached(ctx, specURL)
+				if err != nil {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					cov.SpecsFailed++
+					return nil
+				}
+				cov.SpecsChecked++
+
+				kinds := securityKinds(spec)
+				if slices.Contains(kinds, kind) {
+					response.APIs = append(response.APIs, SecurityMatch{
+						ID:      apiID(rec),
+						Name:    apiName(rec),
+						Schemes: kinds,
+					})
+				}
+				return nil
+			})
+			if err != nil {
+				return newToolCallErrorResult("Error fetching APIs: %v", err)
+			}
+
+			cov.PagesScanned = pages
+			cov.Truncated = cov.Truncated || more
+
+			result, err := marshalResult(response, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// securityKinds returns the (sorted, unique) kinds of the security schemes
+// declared in an OpenAPI 3.x (`components.securitySchemes`) or Swagger 2.0
+// (`securityDefinitions`) document.
+func securityKinds(spec map[string]any) []string {
+	schemes, _ := spec["securityDefinitions"].(map[string]any)
+	if components, ok := spec["components"].(map[string]any); ok {
+		schemes, _ = components["securitySchemes"].(map[string]any)
+	}
+
+	kinds := []string{}
+	for _, v := range schemes {
+		scheme, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var kind string
+		switch t := strings.ToLower(recordString(scheme, "type")); t {
+		case "http":
+			kind = "http-" + strings.ToLower(recordString(scheme, "scheme"))
+		case "basic":
+			kind = "http-basic"
+		default:
+			kind = t
+		}
+
+		if kind != "" && !slices.Contains(kinds, kind) {
+			kinds = append(kinds, kind)
+		}
+	}
+	slices.Sort(kinds)
+
+	return kinds
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	t.Run("waits for in-flight calls before stopping the server", func(t *testing.T) {
+		var calls callTracker
+		if !calls.begin() {
+			t.Fatal("expected call to begin")
+		}
+
+		var stopped, finished atomic.Bool
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			finished.Store(true)
+			calls.done()
+		}()
+
+		shutdown(context.Background(), &calls, func() {
+			if !finished.Load() {
+				t.Error("server stopped before the in-flight call finished")
+			}
+			stopped.Store(true)
+		}, nil)
+
+		if !stopped.Load() {
+			t.Error("server wasn't stopped")
+		}
+		if calls.begin() {
+			t.Error("expected new calls to be rejected after shutdown")
+		}
+	})
+
+	t.Run("returns when the timeout expires", func(t *testing.T) {
+		var calls callTracker
+		calls.begin()
+		defer calls.done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		var stopped atomic.Bool
+		done := make(chan struct{})
+		go func() {
+			shutdown(ctx, &calls, func() { stopped.Store(true) }, nil)
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("shutdown didn't return after the timeout")
+		}
+		if !stopped.Load() {
+			t.Error("server wasn't stopped after the timeout")
+		}
+	})
+
+	t.Run("shuts down the HTTP server", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		httpServer := &http.Server{Handler: http.NotFoundHandler()}
+		served := make(chan error, 1)
+		go func() { served <- httpServer.Serve(listener) }()
+
+		var calls callTracker
+		shutdown(context.Background(), &calls, func() {}, httpServer)
+
+		select {
+		case err := <-served:
+			if err != http.ErrServerClosed {
+				t.Errorf("Serve() error = %v, want %v", err, http.ErrServerClosed)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("HTTP server still serving after shutdown")
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Placeholder values used when an API has no (usable) OpenAPI specification.
+const (
+	placeholderBaseURL = "https://{API_BASE_URL}"
+	placeholderPath    = "/{PATH}"
+)
+
+// shellQuote quotes s as a single POSIX shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Code snippet templates, keyed by language. Values are quoted for the
+// language, as server URLs and paths come from third-party specifications.
+var snippetTemplates = map[string]*template.Template{
+	"curl": template.Must(template.New("curl").Funcs(template.FuncMap{"shellQuote": shellQuote}).Parse(`{{with .Comment}}# {{.}}
+{{end}}curl -X {{.Method}} {{shellQuote .URL}} \
+  -H 'Accept: application/json'
+`)),
+	"python": template.Must(template.New("python").Parse(`{{with .Comment}}# {{.}}
+{{end}}import requests
+
+response = requests.request({{printf "%q" .Method}}, {{printf "%q" .URL}}, headers={"Accept": "application/json"})
+response.raise_for_status()
+print(response.json())
+`)),
+	"go": template.Must(template.New("go").Parse(`{{with .Comment}}// {{.}}
+{{end}}package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+func main() {
+	req, err := http.NewRequest({{printf "%q" .Method}}, {{printf "%q" .URL}}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(body))
+}
+`)),
+}
+
+// GenerateSnippetParams represents the parameters for the generateSnippet tool.
+// The `id` parameter is required. The `language` parameter is optional and
+// defaults to "curl".
+type GenerateSnippetParams struct {
+	ID       string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+	Language string `json:"language,omitempty" jsonschema:"enum=curl,enum=python,enum=go" jsonschema_description:"Language of the snippet. Defaults to curl."`
+}
+
+// snippetData holds the values a snippet template is rendered with.
+type snippetData struct {
+	Comment string
+	Method  string
+	URL     string
+}
+
+// createGenerateSnippetTool creates a tool for generating a minimal client
+// code snippet for an API.
+func createGenerateSnippetTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GenerateSnippetParams]{
+		Name: "generate_snippet",
+		Description: "Generate a minimal code snippet calling an API by ID, based on the server URL and a sample " +
+			`operation from its OpenAPI specification. Supported languages: "curl" (default), "python" and "go".`,
+		HandleFunc: func(ctx context.Context, params GenerateSnippetParams) *mcp.CallToolResult {
+			language := strings.ToLower(params.Language)
+			if language == "" {
+				language = "curl"
+			}
+			tmpl, ok := snippetTemplates[language]
+			if !ok {
+				return newToolCallErrorResult("Unsupported language %q, must be one of: curl, python, go", params.Language)
+			}
+
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			data := snippetData{
+				Comment: "Generic example: replace the placeholders with the API's base URL and an endpoint path.",
+				Method:  "GET",
+				URL:     placeholderBaseURL + placeholderPath,
+			}
+
+			if specURL := specificationURL(api); specURL != "" {
+				if spec, err := fetchSpec(ctx, specURL); err == nil {
+					data = snippetDataFromSpec(spec, specURL)
+				}
+			}
+
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, data); err != nil {
+				return newToolCallErrorResult("Error generating snippet: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: sb.String(),
+					},
+				},
+			}
+		},
+	})
+}
+
+// snippetDataFromSpec derives the template values from an OpenAPI document.
+// Missing parts are filled in with placeholders.
+func snippetDataFromSpec(spec map[string]any, specURL string) snippetData {
+	data := snippetData{
+		Method: "GET",
+		URL:    serverURL(spec, specURL),
+	}
+
+	path, method, op := sampleOperation(spec)
+	if path == "" {
+		data.URL += placeholderPath
+		return data
+	}
+
+	data.Method = strings.ToUpper(method)
+	data.URL = strings.TrimRight(data.URL, "/") + path
+	if summary, ok := op["summary"].(string); ok {
+		data.Comment = strings.Join(strings.Fields(summary), " ")
+	}
+
+	return data
+}
+
+// serverURL returns the base URL of the API described by an OpenAPI 3.x
+// (`servers`) or Swagger 2.0 (`host`, `basePath`, `schemes`) document. Relative
+// server URLs are resolved against the specification URL.
+func serverURL(spec map[string]any, specURL string) string {
+	if servers, ok := spec["servers"].([]any); ok && len(servers) > 0 {
+		if server, ok := servers[0].(map[string]any); ok {
+			if u, ok := server["url"].(string); ok && u != "" {
+				return resolveReference(specURL, u)
+			}
+		}
+	}
+
+	if host, ok := spec["host"].(string); ok && host != "" {
+		scheme := "https"
+		if schemes := recordStrings(spec, "schemes"); len(schemes) > 0 && !slices.Contains(schemes, "https") {
+			scheme = schemes[0]
+		}
+		basePath, _ := spec["basePath"].(string)
+		return scheme + "://" + host + strings.TrimRight(basePath, "/")
+	}
+
+	return placeholderBaseURL
+}
+
+// resolveReference resolves ref against base, returning ref unchanged if
+// either fails to parse.
+func resolveReference(base, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// sampleOperation picks a representative operation from an OpenAPI document:
+// the first GET operation (by path) without path parameters, falling back to
+// the first operation found.
+func sampleOperation(spec map[string]any) (string, string, map[string]any) {
+	paths, _ := spec["paths"].(map[string]any)
+
+	keys := make([]string, 0, len(paths))
+	for k := range paths {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var (
+		fallbackPath, fallbackMethod string
+		fallbackOp                   map[string]any
+	)
+	for _, path := range keys {
+		pathItem, ok := paths[path].(map[string]any)
+		if !ok {
+			continue
+		}
+		for _, method := range oasMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if method == "get" && !strings.Contains(path, "{") {
+				return path, method, op
+			}
+			if fallbackOp == nil {
+				fallbackPath, fallbackMethod, fallbackOp = path, method, op
+			}
+		}
+	}
+
+	return fallbackPath, fallbackMethod, fallbackOp
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares got to the golden file testdata/name, or rewrites the
+// file when running with -update.
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output doesn't match %v\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestGenerateSnippet(t *testing.T) {
+	allowPrivateIPs(t)
+
+	// The base path contains a single quote, which must not end the quoted
+	// URL of the curl snippet.
+	const spec = `{
+		"swagger": "2.0",
+		"host": "api.example.com",
+		"basePath": "/o'neil; rm -rf ~",
+		"paths": {
+			"/items/{id}": {"delete": {}},
+			"/items": {"get": {"summary": "List  items"}}
+		}
+	}`
+
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/with-spec":
+			fixture{body: `{"id":"with-spec","environments":[{"name":"production","specification_url":"` + srvURL + `/openapi.json"}]}`}.serve(w)
+		case "/apis/without-spec":
+			fixture{body: `{"id":"without-spec"}`}.serve(w)
+		case "/openapi.json":
+			fixture{body: spec}.serve(w)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	srvURL = srv.URL
+
+	for _, language := range []string{"curl", "python", "go"} {
+		t.Run(language, func(t *testing.T) {
+			res := callTool(t, createGenerateSnippetTool(), `{"id":"with-spec","language":"`+language+`"}`)
+			if res.IsError {
+				t.Fatalf("unexpected error result: %v", resultText(t, res))
+			}
+			checkGolden(t, filepath.Join("snippets", language+".golden"), resultText(t, res))
+		})
+
+		t.Run(language+" placeholder", func(t *testing.T) {
+			res := callTool(t, createGenerateSnippetTool(), `{"id":"without-spec","language":"`+language+`"}`)
+			if res.IsError {
+				t.Fatalf("unexpected error result: %v", resultText(t, res))
+			}
+			checkGolden(t, filepath.Join("snippets", language+"_placeholder.golden"), resultText(t, res))
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"":                          "''",
+		"https://example.com/a?b=c": "'https://example.com/a?b=c'",
+		"o'neil":                    `'o'\''neil'`,
+		"$(id)`id`":                 "'$(id)`id`'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
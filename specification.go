@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of OpenAPI documents.
+const (
+	specFormatJSON = "json"
+	specFormatYAML = "yaml"
+)
+
+// GetAPISpecificationParams represents the parameters for the getAPISpecification tool.
+// The `id` parameter is required.
+type GetAPISpecificationParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// createGetAPISpecificationTool creates a tool for getting the OpenAPI
+// specification of an API.
+func createGetAPISpecificationTool() mcp.Tool {
+	return createTool(mcp.ToolDef[GetAPISpecificationParams]{
+		Name: "get_api_specification",
+		Description: "Get the OpenAPI specification of an API by ID. The document is returned as-is, in JSON or " +
+			"YAML, as published by the API provider.",
+		HandleFunc: func(ctx context.Context, params GetAPISpecificationParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			specURL := specificationURL(api)
+			if specURL == "" {
+				return newToolCallErrorResult("API with ID %v has no OpenAPI specification URL", params.ID)
+			}
+
+			body, mediaType, err := readSpec(ctx, specURL)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching OpenAPI specification: %v", err)
+			}
+
+			format := specFormat(mediaType, specURL, body)
+			if err := checkSpecSyntax(body, format); err != nil {
+				return newToolCallErrorResult("OpenAPI specification at %v is not a valid %v document: %v", specURL, strings.ToUpper(format), err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(body),
+					},
+				},
+			}
+		},
+	})
+}
+
+// specFormat detects the format of an OpenAPI document: by media type, then by
+// file extension, and finally by its first non-whitespace character.
+func specFormat(mediaType, specURL string, body []byte) string {
+	switch {
+	case strings.Contains(mediaType, "json"):
+		return specFormatJSON
+	case strings.Contains(mediaType, "yaml"):
+		return specFormatYAML
+	}
+
+	if u, err := url.Parse(specURL); err == nil {
+		switch strings.ToLower(path.Ext(u.Path)) {
+		case ".json":
+			return specFormatJSON
+		case ".yaml", ".yml":
+			return specFormatYAML
+		}
+	}
+
+	if trimmed := strings.TrimSpace(string(body)); strings.HasPrefix(trimmed, "{") {
+		return specFormatJSON
+	}
+	return specFormatYAML
+}
+
+// checkSpecSyntax checks that an OpenAPI document is a syntactically valid
+// JSON or YAML object.
+func checkSpecSyntax(body []byte, format string) error {
+	var spec map[string]any
+	if format == specFormatJSON {
+		return json.Unmarshal(body, &spec)
+	}
+	if err := yaml.Unmarshal(body, &spec); err != nil {
+		return err
+	}
+	if spec == nil {
+		return errors.New("empty document")
+	}
+	return nil
+}
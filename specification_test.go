@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetAPISpecification(t *testing.T) {
+	allowPrivateIPs(t)
+
+	openapi3, err := os.ReadFile(filepath.Join("testdata", "oas", "openapi3.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	swagger2, err := os.ReadFile(filepath.Join("testdata", "oas", "swagger2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/openapi.yaml":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(openapi3)
+		case "/swagger":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(swagger2)
+		case "/broken.json":
+			w.Write([]byte(`{"swagger": "2.0",`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(specs.Close)
+
+	api := func(id, specPath string) fixture {
+		return fixture{body: `{"id":"` + id + `","environments":[` +
+			`{"name":"acceptance","specification_url":"` + specs.URL + `/missing.json"},` +
+			`{"name":"production","specification_url":"` + specs.URL + specPath + `"}]}`}
+	}
+	newFixtureServer(t, map[string]fixture{
+		"/apis/yaml":    api("yaml", "/openapi.yaml"),
+		"/apis/json":    api("json", "/swagger"),
+		"/apis/broken":  api("broken", "/broken.json"),
+		"/apis/missing": api("missing", "/gone.yaml"),
+		"/apis/none":    {body: `{"id":"none","environments":[{"name":"production"}]}`},
+	})
+
+	t.Run("YAML document as-is", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"yaml"}`)
+		if text := resultText(t, res); res.IsError || text != string(openapi3) {
+			t.Errorf("result = %q, want the production document as-is", text)
+		}
+	})
+
+	t.Run("JSON document as-is", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"json"}`)
+		if text := resultText(t, res); res.IsError || text != string(swagger2) {
+			t.Errorf("result = %q, want the production document as-is", text)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"broken"}`)
+		expectError(t, res, "is not a valid JSON document")
+	})
+
+	t.Run("document not found", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"missing"}`)
+		expectError(t, res, "Error fetching OpenAPI specification")
+	})
+
+	t.Run("no specification URL", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"none"}`)
+		expectError(t, res, "API with ID none has no OpenAPI specification URL")
+	})
+
+	t.Run("API not found", func(t *testing.T) {
+		res := callTool(t, createGetAPISpecificationTool(), `{"id":"unknown"}`)
+		expectError(t, res, "API with ID unknown not found")
+	})
+}
+
+func TestSpecFormat(t *testing.T) {
+	tests := []struct {
+		mediaType, url, body, want string
+	}{
+		{"application/json", "https://example.com/openapi.yaml", "openapi: 3.0.0", specFormatJSON},
+		{"application/yaml", "https://example.com/openapi.json", "{}", specFormatYAML},
+		{"", "https://example.com/openapi.JSON", "openapi: 3.0.0", specFormatJSON},
+		{"text/plain", "https://example.com/openapi.yml", "{}", specFormatYAML},
+		{"text/plain", "https://example.com/openapi", "  {\"openapi\": \"3.0.0\"}", specFormatJSON},
+		{"text/plain", "https://example.com/openapi", "openapi: 3.0.0", specFormatYAML},
+	}
+
+	for _, tt := range tests {
+		if got := specFormat(tt.mediaType, tt.url, []byte(tt.body)); got != tt.want {
+			t.Errorf("specFormat(%q, %q, %q) = %v, want %v", tt.mediaType, tt.url, tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// StreamCatalogParams represents the parameters for the streamCatalog tool,
+// which takes none.
+type StreamCatalogParams struct{}
+
+// StreamCatalogSummary represents the summary following the records in the
+// result of the streamCatalog tool.
+type StreamCatalogSummary struct {
+	PagesScanned int    `json:"pages_scanned"`
+	Truncated    bool   `json:"truncated,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// createStreamCatalogTool creates a tool returning the API records of the
+// catalog as NDJSON. It's the buffered counterpart of handleCatalogStream:
+// go-mcp can't stream partial tool results, so on every transport the records
+// of all pages (up to the page budget) are returned at once.
+func createStreamCatalogTool() mcp.Tool {
+	return createTool(mcp.ToolDef[StreamCatalogParams]{
+		Name: "stream_catalog",
+		Description: "Get the API records of the whole catalog as NDJSON (one record per line), following pagination, " +
+			"followed by a summary with `pages_scanned` and `truncated`. The number of pages is bounded. Tool results " +
+			"can't be streamed, so the records are returned at once; when the server is served over HTTP, " +
+			"`GET /catalog/stream` streams them as Server-Sent Events instead.",
+		HandleFunc: func(ctx context.Context, _ StreamCatalogParams) *mcp.CallToolResult {
+			var records bytes.Buffer
+			pages, more, err := walkPages(ctx, "apis", maxPages, func(item json.RawMessage) error {
+				item, err := normalizeRecords(item)
+				if err != nil {
+					return err
+				}
+				if err := json.Compact(&records, item); err != nil {
+					return err
+				}
+				records.WriteByte('\n')
+				return nil
+			})
+
+			summary := StreamCatalogSummary{
+				PagesScanned: pages,
+				Truncated:    more,
+			}
+			if err != nil {
+				// A timed out page still yields the records gathered so far.
+				if !errors.Is(err, errPageTimeout) {
+					return newToolCallErrorResult("Error fetching APIs: %v", err)
+				}
+				summary.Error = err.Error()
+				summary.Truncated = true
+			}
+
+			result, err := marshalResult(summary, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: records.String(),
+					},
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// handleCatalogStream streams the API records of the catalog as Server-Sent
+// Events, emitting each page's records as soon as it's fetched rather than
+// buffering the whole catalog. Every record is sent as an `api` event; a final
+// `done` (or `error`) event reports the number of pages fetched. The number of
+// pages is bounded by the page budget.
+//
+// The MCP tool interface has no way to stream partial tool results, so this is
+// exposed as a plain HTTP endpoint next to the MCP routes instead. The
+// streamCatalog tool returns the same records buffered, on every transport.
+func handleCatalogStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	pages, more, err := walkPages(r.Context(), "apis", maxPages, func(item json.RawMessage) error {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, item); err != nil {
+			return err
+		}
+		if err := writeEvent(w, "api", buf.Bytes()); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+
+	summary, _ := json.Marshal(map[string]any{
+		"pages_scanned": pages,
+		"truncated":     more,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "Error streaming catalog", "error", err)
+		summary, _ = json.Marshal(map[string]any{
+			"pages_scanned": pages,
+			"error":         err.Error(),
+		})
+		_ = writeEvent(w, "error", summary)
+	} else {
+		_ = writeEvent(w, "done", summary)
+	}
+	flusher.Flush()
+}
+
+// writeEvent writes a single Server-Sent Event. The data must not contain
+// newlines, which holds for compacted JSON.
+func writeEvent(w http.ResponseWriter, event string, data []byte) error {
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
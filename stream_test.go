@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+func TestCatalogStreamIsIncremental(t *testing.T) {
+	const perPage = 2
+
+	// The second page is only served once the events of the first page
+	// have been received, which deadlocks (until the timeout) if the first
+	// page is buffered.
+	release := make(chan struct{})
+	var timedOut atomic.Bool
+	newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		header := http.Header{}
+		switch page {
+		case "1":
+			header.Set("Link", `</apis?page=2>; rel="next"`)
+		case "2":
+			select {
+			case <-release:
+			case <-time.After(2 * time.Second):
+				timedOut.Store(true)
+			}
+		default:
+			fixture{status: http.StatusNotFound}.serve(w)
+			return
+		}
+		fixture{header: header, body: fmt.Sprintf(`[{"id":"%v-0"},{"id":"%v-1"}]`, page, page)}.serve(w)
+	}))
+
+	srv := httptest.NewServer(http.HandlerFunc(handleCatalogStream))
+	t.Cleanup(srv.Close)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	var events []string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if event, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, event)
+		}
+		if d, ok := strings.CutPrefix(line, "data: "); ok {
+			data = append(data, d)
+			if len(data) == perPage {
+				close(release)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if timedOut.Load() {
+		t.Error("the events of page 1 weren't emitted before page 2 was fetched")
+	}
+	want := []string{"api", "api", "api", "api", "done"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %q, want %q", events, want)
+	}
+	for i, id := range []string{"1-0", "1-1", "2-0", "2-1"} {
+		var rec struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal([]byte(data[i]), &rec); err != nil || rec.ID != id {
+			t.Errorf("event %d data = %q, want record %v", i, data[i], id)
+		}
+	}
+	if done := data[len(data)-1]; done != `{"pages_scanned":2,"truncated":false}` {
+		t.Errorf("done event data = %q", done)
+	}
+}
+
+func TestStreamCatalogTool(t *testing.T) {
+	t.Run("all pages", func(t *testing.T) {
+		newMockCatalog(t, 3, 2)
+
+		res := callTool(t, createStreamCatalogTool(), `{}`)
+		if res.IsError || len(res.Content) != 2 {
+			t.Fatalf("got %v, want records and a summary", res.Content)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(res.Content[0].(mcp.TextContent).Text, "\n"), "\n")
+		if len(lines) != 6 {
+			t.Fatalf("got %d records, want 6", len(lines))
+		}
+		for _, line := range lines {
+			if !json.Valid([]byte(line)) {
+				t.Errorf("line %q isn't valid JSON", line)
+			}
+		}
+
+		var summary StreamCatalogSummary
+		decodeResult(t, res, &summary)
+		if summary != (StreamCatalogSummary{PagesScanned: 3}) {
+			t.Errorf("summary = %+v, want 3 pages scanned", summary)
+		}
+	})
+
+	t.Run("page budget", func(t *testing.T) {
+		newMockCatalog(t, 3, 2)
+		maxPages = 2
+
+		res := callTool(t, createStreamCatalogTool(), `{}`)
+
+		var summary StreamCatalogSummary
+		decodeResult(t, res, &summary)
+		if summary != (StreamCatalogSummary{PagesScanned: 2, Truncated: true}) {
+			t.Errorf("summary = %+v, want 2 pages scanned and truncated", summary)
+		}
+		if n := strings.Count(res.Content[0].(mcp.TextContent).Text, "\n"); n != 4 {
+			t.Errorf("got %d records, want 4", n)
+		}
+	})
+
+	t.Run("upstream error", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {status: http.StatusInternalServerError, body: `{"message":"boom"}`},
+		})
+
+		expectError(t, callTool(t, createStreamCatalogTool(), `{}`), "boom")
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// structuredMIMEType is the MIME type of tool results returned as structured
+// content.
+const structuredMIMEType = "application/json"
+
+// jsonContent is a tool result content item carrying a JSON document as an
+// embedded resource. Unlike text content, the document is typed with the
+// application/json MIME type, so clients can hand it on as data without
+// guessing whether the text is JSON. go-mcp has no structured result field,
+// so the embedded resource is the closest the protocol offers.
+type jsonContent struct {
+	URI  string
+	Data json.RawMessage
+}
+
+func (c jsonContent) MarshalJSON() ([]byte, error) {
+	type resource struct {
+		URI      string `json:"uri"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	}
+	return json.Marshal(struct {
+		Type     string   `json:"type"`
+		Resource resource `json:"resource"`
+	}{
+		Type: "resource",
+		Resource: resource{
+			URI:      c.URI,
+			MimeType: structuredMIMEType,
+			Text:     string(c.Data),
+		},
+	})
+}
+
+// structuredResultURI returns the URI identifying the result of a call to the
+// named tool.
+func structuredResultURI(tool string) string {
+	return fmt.Sprintf("tool://%s/result", tool)
+}
+
+// structureResult converts the JSON text content items of a successful tool
+// result to JSON resource content items. Error results and non-JSON text, such
+// as warnings, are returned unchanged.
+func structureResult(tool string, result *mcp.CallToolResult) *mcp.CallToolResult {
+	if result == nil || result.IsError {
+		return result
+	}
+
+	content := make([]mcp.Content, len(result.Content))
+	for i, c := range result.Content {
+		content[i] = c
+		text, ok := c.(mcp.TextContent)
+		if !ok || !json.Valid([]byte(text.Text)) {
+			continue
+		}
+		content[i] = jsonContent{
+			URI:  structuredResultURI(tool),
+			Data: json.RawMessage(text.Text),
+		}
+	}
+
+	return &mcp.CallToolResult{Content: content}
+}
+
+// withStructuredOutput wraps a tool handler, converting its result with
+// structureResult.
+func withStructuredOutput[T any](tool string, handle func(context.Context, T) *mcp.CallToolResult) func(context.Context, T) *mcp.CallToolResult {
+	return func(ctx context.Context, params T) *mcp.CallToolResult {
+		return structureResult(tool, handle(ctx, params))
+	}
+}
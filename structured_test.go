@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// setStructuredOutput sets -structured-output for the duration of the test.
+// Tools must be created after calling it.
+func setStructuredOutput(t *testing.T, enabled bool) {
+	t.Helper()
+
+	old := structuredOutput
+	t.Cleanup(func() { structuredOutput = old })
+	structuredOutput = enabled
+}
+
+func TestStructuredOutput(t *testing.T) {
+	const record = `{"id":"a","title":"Test API"}`
+
+	t.Run("text", func(t *testing.T) {
+		setStructuredOutput(t, false)
+		newFixtureServer(t, map[string]fixture{"/apis/a": {body: record}})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		if len(res.Content) != 1 {
+			t.Fatalf("got %d content items, want 1", len(res.Content))
+		}
+		text, ok := res.Content[0].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("content is %T, want text", res.Content[0])
+		}
+		if text.Text != record {
+			t.Errorf("text = %v, want %v", text.Text, record)
+		}
+	})
+
+	t.Run("structured", func(t *testing.T) {
+		setStructuredOutput(t, true)
+		newFixtureServer(t, map[string]fixture{"/apis/a": {body: record}})
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"a"}`)
+		if res.IsError || len(res.Content) != 1 {
+			t.Fatalf("got %#v, want a single content item", res)
+		}
+
+		data, err := json.Marshal(res.Content[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got struct {
+			Type     string `json:"type"`
+			Resource struct {
+				URI      string `json:"uri"`
+				MimeType string `json:"mimeType"`
+				Text     string `json:"text"`
+			} `json:"resource"`
+		}
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Type != "resource" || got.Resource.MimeType != "application/json" {
+			t.Errorf("got %s, want an application/json resource", data)
+		}
+		if got.Resource.URI != "tool://get_api/result" {
+			t.Errorf("uri = %v, want tool://get_api/result", got.Resource.URI)
+		}
+		if got.Resource.Text != record {
+			t.Errorf("text = %v, want %v", got.Resource.Text, record)
+		}
+	})
+
+	t.Run("structured error", func(t *testing.T) {
+		setStructuredOutput(t, true)
+		newFixtureServer(t, nil)
+
+		res := callTool(t, createGetAPITool(httpClient, apiBaseURL), `{"id":"missing"}`)
+		expectError(t, res, "not found")
+	})
+}
+
+func TestStructureResult(t *testing.T) {
+	res := structureResult("get_api", &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Text: "Warning: API a is deprecated"},
+			mcp.TextContent{Text: `{"id":"a"}`},
+		},
+	})
+
+	if _, ok := res.Content[0].(mcp.TextContent); !ok {
+		t.Errorf("plain-text content is %T, want it kept as text", res.Content[0])
+	}
+	if c, ok := res.Content[1].(jsonContent); !ok || string(c.Data) != `{"id":"a"}` {
+		t.Errorf("JSON content is %#v, want structured content", res.Content[1])
+	}
+}
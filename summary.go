@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// APISummary represents the metadata of an API returned by the
+// listAPIsSummary tool: a projection of the full API record, to save tokens.
+type APISummary struct {
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Type         string `json:"type,omitempty"`
+}
+
+// ListAPIsSummaryResponse represents the response from the listAPIsSummary tool.
+// Its pagination info is the same as that of ListAPIsResponse.
+type ListAPIsSummaryResponse struct {
+	APIs       []APISummary `json:"apis"`
+	NextPage   int          `json:"next_page,omitempty"`
+	PrevPage   int          `json:"prev_page,omitempty"`
+	LastPage   int          `json:"last_page,omitempty"`
+	TotalCount int          `json:"total_count,omitempty"`
+}
+
+// createListAPIsSummaryTool creates a tool for listing APIs with only their
+// metadata. It takes the same parameters as the listAPIs tool.
+func createListAPIsSummaryTool(client *http.Client, baseURL string) mcp.Tool {
+	return createTool(mcp.ToolDef[ListAPIsParams]{
+		Name: "list_apis_summary",
+		Description: "List APIs like list_apis, but with only their metadata, to save tokens: `id`, `name`, " +
+			"`organization` (name), `description` and `type`. Use get_api for the full record of an API.",
+		HandleFunc: func(ctx context.Context, params ListAPIsParams) *mcp.CallToolResult {
+			page, err := resolvePage(params.Page)
+			if err != nil {
+				return newToolCallErrorResult("Invalid page: %v", err)
+			}
+
+			apiURL, err := buildURL(baseURL, listAPIsQuery(page, params), "apis")
+			if err != nil {
+				return newToolCallErrorResult("Error building URL: %v", err)
+			}
+
+			lp, err := fetchList(ctx, client, apiURL, page)
+			if err != nil {
+				return newFetchErrorResult(ctx, "APIs", err)
+			}
+			apis := lp.Body
+
+			if org := strings.TrimSpace(params.Organization); org != "" {
+				apis, err = filterByOrganization(apis, org)
+				if err != nil {
+					return newToolCallErrorResult("Error filtering response: %v", err)
+				}
+			}
+
+			summaries, err := summarizeAPIs(apis)
+			if err != nil {
+				return newToolCallErrorResult("Error parsing response: %v", err)
+			}
+
+			items, err := json.Marshal(summaries)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			response := ListAPIsSummaryResponse{
+				APIs:       summaries,
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			}
+
+			return newListResult(response, items, ListPagination{
+				NextPage:   lp.NextPage,
+				PrevPage:   lp.PrevPage,
+				LastPage:   lp.LastPage,
+				TotalCount: lp.TotalCount,
+			})
+		},
+	})
+}
+
+// summarizeAPIs projects a list of API records down to their metadata.
+func summarizeAPIs(body json.RawMessage) ([]APISummary, error) {
+	items, err := decodeItems(body)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]APISummary, 0, len(items))
+	for _, item := range items {
+		var rec map[string]any
+		if err := json.Unmarshal(item, &rec); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, APISummary{
+			ID:           apiID(rec),
+			Name:         apiName(rec),
+			Organization: apiOrganization(rec),
+			Description:  strings.Join(strings.Fields(recordString(rec, "description")), " "),
+			Type:         apiType(rec),
+		})
+	}
+
+	return summaries, nil
+}
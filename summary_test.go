@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestListAPIsSummary(t *testing.T) {
+	t.Run("success with pagination", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": listPageFixture("/apis", `[{"id":"a","title":"A","description":"An\n  API","organization":{"name":"Org"},"type":"rest_json"}]`, 3, 1, 5, 42),
+		})
+
+		res := callTool(t, createListAPIsSummaryTool(httpClient, apiBaseURL), `{"page":2}`)
+
+		var got struct {
+			APIs []APISummary `json:"apis"`
+			listPageResult
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.APIs) != 1 {
+			t.Fatalf("got %d APIs, want 1", len(got.APIs))
+		}
+		if got.APIs[0].ID != "a" || got.APIs[0].Description != "An API" {
+			t.Errorf("summary = %+v, want id a and description %q", got.APIs[0], "An API")
+		}
+		want := listPageResult{NextPage: 3, PrevPage: 1, LastPage: 5, TotalCount: 42}
+		if got.listPageResult != want {
+			t.Errorf("pagination = %+v, want %+v", got.listPageResult, want)
+		}
+	})
+
+	t.Run("only metadata fields", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {body: `[{
+				"id": "a",
+				"title": "A",
+				"description": "An API",
+				"organization": {"name": "Org", "ooid": 42},
+				"api_type": "rest_json",
+				"environments": [{"name": "production", "api_url": "https://example.com"}],
+				"contact": {"email": "api@example.com"},
+				"_links": {"self": {"href": "/apis/a"}}
+			}]`},
+		})
+
+		res := callTool(t, createListAPIsSummaryTool(httpClient, apiBaseURL), `{}`)
+
+		var got struct {
+			APIs []map[string]any `json:"apis"`
+		}
+		decodeResult(t, res, &got)
+
+		if len(got.APIs) != 1 {
+			t.Fatalf("got %d APIs, want 1", len(got.APIs))
+		}
+		want := map[string]any{
+			"id":           "a",
+			"name":         "A",
+			"organization": "Org",
+			"description":  "An API",
+			"type":         "rest_json",
+		}
+		if !reflect.DeepEqual(got.APIs[0], want) {
+			t.Errorf("summary = %v, want %v", got.APIs[0], want)
+		}
+	})
+
+	t.Run("malformed body", func(t *testing.T) {
+		newFixtureServer(t, map[string]fixture{
+			"/apis": {body: `[{"id":`},
+		})
+
+		res := callTool(t, createListAPIsSummaryTool(httpClient, apiBaseURL), `{}`)
+		expectError(t, res, "[decode]")
+	})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+)
+
+// tlsConfig returns the TLS configuration for connections to upstream servers.
+// If caCertFile is set, server certificates are verified against the system
+// roots extended with the CA certificates in that PEM file, e.g. for TLS
+// intercepting proxies or private CAs. insecureSkipVerify disables
+// verification altogether, which is only meant for local testing.
+func tlsConfig(caCertFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+	if caCertFile == "" {
+		return config, nil
+	}
+
+	pem, err := os.ReadFile(caCertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+	config.RootCAs = pool
+
+	return config, nil
+}
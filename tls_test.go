@@ -0,0 +1,80 @@
+package main
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSConfig(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	// Rejected handshakes are expected.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	caCertFile := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caCertFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(t *testing.T, caCertFile string, insecureSkipVerify bool) error {
+		t.Helper()
+
+		config, err := tlsConfig(caCertFile, insecureSkipVerify)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = config
+		t.Cleanup(transport.CloseIdleConnections)
+
+		resp, err := (&http.Client{Transport: transport}).Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	}
+
+	t.Run("custom CA", func(t *testing.T) {
+		if err := get(t, caCertFile, false); err != nil {
+			t.Errorf("request with -ca-cert failed: %v", err)
+		}
+	})
+
+	t.Run("without custom CA", func(t *testing.T) {
+		err := get(t, "", false)
+		var unknownAuthority x509.UnknownAuthorityError
+		if !errors.As(err, &unknownAuthority) {
+			t.Errorf("got error %v, want an unknown authority error", err)
+		}
+	})
+
+	t.Run("insecure", func(t *testing.T) {
+		if err := get(t, "", true); err != nil {
+			t.Errorf("request with -insecure-skip-verify failed: %v", err)
+		}
+	})
+
+	t.Run("invalid CA file", func(t *testing.T) {
+		notPEM := filepath.Join(dir, "not.pem")
+		if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		for _, file := range []string{notPEM, filepath.Join(dir, "missing.pem")} {
+			if _, err := tlsConfig(file, false); err == nil {
+				t.Errorf("tlsConfig(%q) succeeded, want an error", file)
+			}
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Default timeout for a single tool call.
+const defaultRequestTimeout = 2 * time.Minute
+
+// Tool calls being handled, tracked so that shutdown can wait for them.
+var inflightCalls callTracker
+
+// callTracker tracks in-flight tool calls. Once draining, new calls are
+// rejected.
+type callTracker struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+}
+
+// begin registers a new call, and reports whether it may proceed. Each call
+// that may proceed must be followed by a call to done.
+func (t *callTracker) begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.draining {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+// done marks a call registered by begin as finished.
+func (t *callTracker) done() {
+	t.wg.Done()
+}
+
+// drain stops accepting new calls and waits for in-flight calls to finish,
+// until ctx is done.
+func (t *callTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// toolContextKey is the context key for the name of the tool being called.
+type toolContextKey struct{}
+
+// contextWithTool returns a copy of ctx carrying the name of the tool being
+// called.
+func contextWithTool(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolContextKey{}, name)
+}
+
+// toolFromContext returns the name of the tool being called, or an empty
+// string if ctx isn't derived from a tool call.
+func toolFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(toolContextKey{}).(string)
+	return name
+}
+
+// createTool wraps mcp.CreateTool, decorating the handler with behavior shared
+// by all tools: the context passed to the handler carries the tool name and a
+// new request ID, so downstream helpers can label their work (and upstream
+// requests) by tool call, and is bounded by the configured request timeout.
+// The incoming deadline still applies when it's earlier. The start and end of
+// each call are logged at debug level. Calls are tracked in inflightCalls, and
+// rejected once the server is shutting down. In dry-run mode, see
+// handleDryRun, and with -include-headers, see handleWithResponseHeaders.
+// Every call is counted in the tool call metrics, and its result is truncated
+// to -max-output-chars. The tool is registered with its name prefixed with
+// -tool-prefix.
+//
+// Handlers must never write to stdout, which carries the JSON-RPC stream of
+// the stdio transport. Diagnostics go through the (stderr) slog logger, and
+// results are returned as *mcp.CallToolResult.
+func createTool[T any](def mcp.ToolDef[T]) mcp.Tool {
+	def.Name = toolPrefix + def.Name
+	handle := def.HandleFunc
+	def.HandleFunc = func(ctx context.Context, params T) *mcp.CallToolResult {
+		if !inflightCalls.begin() {
+			return newToolCallErrorResult("Server is shutting down")
+		}
+		defer inflightCalls.done()
+
+		ctx = contextWithRequestID(contextWithTool(ctx, def.Name), newRequestID())
+		start := time.Now()
+		slog.DebugContext(ctx, "Tool call started", "tool", def.Name)
+
+		if requestTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+			defer cancel()
+		}
+
+		var result *mcp.CallToolResult
+		switch {
+		case dryRun:
+			result = handleDryRun(ctx, handle, params)
+		case includeHeaders:
+			result = handleWithResponseHeaders(ctx, handle, params)
+		default:
+			result = handle(ctx, params)
+		}
+		observeToolCall(def.Name, result == nil || result.IsError)
+		slog.DebugContext(ctx, "Tool call finished", "tool", def.Name, "is_error", result == nil || result.IsError,
+			"elapsed", time.Since(start))
+		truncateResult(result, maxOutputChars)
+
+		return result
+	}
+	if structuredOutput {
+		def.HandleFunc = withStructuredOutput(def.Name, def.HandleFunc)
+	}
+	return mcp.CreateTool(def)
+}
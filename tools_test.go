@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+func TestValidateToolPrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		wantErr string
+	}{
+		{"", ""},
+		{"doa_", ""},
+		{"DOA-v2_", ""},
+		{strings.Repeat("a", maxToolPrefixLen), ""},
+		{strings.Repeat("a", maxToolPrefixLen+1), "longer than"},
+		{"doa.", "invalid character '.'"},
+		{"doa ", "invalid character ' '"},
+		{"doa/", "invalid character '/'"},
+		{"dóa_", "invalid character 'ó'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			err := validateToolPrefix(tt.prefix)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateToolPrefix(%q) = %v, want nil", tt.prefix, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateToolPrefix(%q) = %v, want error containing %q", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestToolPrefix(t *testing.T) {
+	old := toolPrefix
+	t.Cleanup(func() { toolPrefix = old })
+	toolPrefix = strings.Repeat("p", maxToolPrefixLen)
+
+	for _, st := range serverTools {
+		t.Run(st.name, func(t *testing.T) {
+			name := st.create().Name
+			if name != toolPrefix+st.name {
+				t.Errorf("tool registered as %q, want %q", name, toolPrefix+st.name)
+			}
+			// Clients commonly allow tool names of up to 64 characters.
+			if len(name) > 64 {
+				t.Errorf("prefixed name %q is longer than 64 characters", name)
+			}
+		})
+	}
+
+	// The tool allowlist takes the unprefixed names.
+	tools, err := selectTools(serverTools, "list_apis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 1 || tools[0].create().Name != toolPrefix+"list_apis" {
+		t.Errorf("selectTools() = %v, want list_apis only", tools)
+	}
+}
+
+func TestToolInputSchemas(t *testing.T) {
+	for _, st := range serverTools {
+		t.Run(st.name, func(t *testing.T) {
+			tool := st.create()
+
+			b, err := json.Marshal(tool.InputSchema)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var schema struct {
+				Properties map[string]struct {
+					Description string `json:"description"`
+				} `json:"properties"`
+				Required []string `json:"required"`
+			}
+			if err := json.Unmarshal(b, &schema); err != nil {
+				t.Fatal(err)
+			}
+
+			for name, prop := range schema.Properties {
+				if prop.Description == "" {
+					t.Errorf("parameter %q has no description", name)
+				}
+			}
+			for _, name := range schema.Required {
+				if _, ok := schema.Properties[name]; !ok {
+					t.Errorf("required parameter %q isn't a property", name)
+				}
+			}
+			if _, ok := schema.Properties["id"]; ok && !slices.Contains(schema.Required, "id") {
+				t.Error("parameter \"id\" isn't required")
+			}
+			if page, ok := schema.Properties["page"]; ok {
+				if slices.Contains(schema.Required, "page") {
+					t.Error("parameter \"page\" is required")
+				}
+				if !strings.Contains(page.Description, "starting at 1") {
+					t.Errorf("description of \"page\" = %q, want it to mention it starts at 1", page.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestToolFromContext(t *testing.T) {
+	if name := toolFromContext(context.Background()); name != "" {
+		t.Errorf("toolFromContext() = %q outside a tool call, want empty", name)
+	}
+
+	type params struct{}
+	var names, requestIDs []string
+	tool := createTool(mcp.ToolDef[params]{
+		Name: "test_tool",
+		HandleFunc: func(ctx context.Context, _ params) *mcp.CallToolResult {
+			names = append(names, toolFromContext(ctx))
+			requestIDs = append(requestIDs, requestIDFromContext(ctx))
+			return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Text: "ok"}}}
+		},
+	})
+
+	callTool(t, tool, `{}`)
+	callTool(t, tool, `{}`)
+
+	if !slices.Equal(names, []string{"test_tool", "test_tool"}) {
+		t.Errorf("tool names in handler = %q, want test_tool", names)
+	}
+	if requestIDs[0] == "" || requestIDs[0] == requestIDs[1] {
+		t.Errorf("request IDs = %q, want a new ID per call", requestIDs)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	old := requestTimeout
+	t.Cleanup(func() { requestTimeout = old })
+	requestTimeout = 50 * time.Millisecond
+
+	type params struct{}
+	var deadlineErr error
+	tool := createTool(mcp.ToolDef[params]{
+		Name: "slow_tool",
+		HandleFunc: func(ctx context.Context, _ params) *mcp.CallToolResult {
+			select {
+			case <-ctx.Done():
+				deadlineErr = ctx.Err()
+				return newToolCallErrorResult("Error: %v", ctx.Err())
+			case <-time.After(5 * time.Second):
+				return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent{Text: "done"}}}
+			}
+		},
+	})
+
+	t.Run("deadline fires while the parent stays alive", func(t *testing.T) {
+		parent, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		start := time.Now()
+		res, err := tool.HandleFunc(parent, json.RawMessage(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectError(t, res, "[timeout]")
+		if !errors.Is(deadlineErr, context.DeadlineExceeded) {
+			t.Errorf("handler context error = %v, want %v", deadlineErr, context.DeadlineExceeded)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("call took %v, want it to time out after %v", elapsed, requestTimeout)
+		}
+		if err := parent.Err(); err != nil {
+			t.Errorf("parent context error = %v, want it alive", err)
+		}
+	})
+
+	t.Run("earlier incoming deadline applies", func(t *testing.T) {
+		requestTimeout = time.Minute
+		t.Cleanup(func() { requestTimeout = 50 * time.Millisecond })
+
+		parent, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		res, err := tool.HandleFunc(parent, json.RawMessage(`{}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expectError(t, res, "[timeout]")
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("call took %v, want the incoming deadline to apply", elapsed)
+		}
+	})
+}
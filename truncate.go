@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// truncateResult truncates the text content of a tool result to at most
+// maxChars characters each, so huge results don't exceed the context window of
+// the client. A maxChars of 0 or less disables truncation.
+func truncateResult(result *mcp.CallToolResult, maxChars int) {
+	if result == nil || maxChars <= 0 {
+		return
+	}
+	for i, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			text.Text = truncateText(text.Text, maxChars)
+			result.Content[i] = text
+		}
+	}
+}
+
+// truncateText returns s truncated to maxChars characters (runes), followed by
+// a marker stating the number of characters omitted. Strings within the limit
+// are returned unchanged.
+func truncateText(s string, maxChars int) string {
+	n := utf8.RuneCountInString(s)
+	if n <= maxChars {
+		return s
+	}
+
+	// Find the byte offset of the first rune beyond the limit, so that no
+	// rune is cut in half.
+	offset, count := 0, 0
+	for i := range s {
+		if count == maxChars {
+			offset = i
+			break
+		}
+		count++
+	}
+
+	return fmt.Sprintf("%v... [truncated, %d characters omitted]", s[:offset], n-maxChars)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		maxChars int
+		want     string
+	}{
+		{"under limit", "hello", 10, "hello"},
+		{"at limit", "hello", 5, "hello"},
+		{"over limit", "hello world", 5, "hello... [truncated, 6 characters omitted]"},
+		{"multi-byte runes", "café crème", 4, "café... [truncated, 6 characters omitted]"},
+		{"cut after multi-byte rune", "ééééé", 3, "ééé... [truncated, 2 characters omitted]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateText(tt.s, tt.maxChars)
+			if got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.s, tt.maxChars, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateText(%q, %d) = %q, which isn't valid UTF-8", tt.s, tt.maxChars, got)
+			}
+		})
+	}
+}
+
+func TestTruncateResult(t *testing.T) {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.TextContent{Text: "short"},
+			mcp.TextContent{Text: "much too long"},
+		},
+	}
+
+	truncateResult(result, 8)
+
+	want := []string{"short", "much too... [truncated, 5 characters omitted]"}
+	for i, content := range result.Content {
+		if got := content.(mcp.TextContent).Text; got != want[i] {
+			t.Errorf("content %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	// Truncation is disabled for a limit of 0.
+	truncateResult(result, 0)
+	if got := result.Content[1].(mcp.TextContent).Text; got != want[1] {
+		t.Errorf("content changed without limit: %q", got)
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dstotijn/go-mcp"
+)
+
+// Timeout for fetching a user-provided OpenAPI document.
+const oasURLTimeout = 15 * time.Second
+
+// Error reported for a user-provided document that can't be decoded. Decode
+// errors may quote the document, which could be any resource the URL points
+// at, so they aren't reported.
+const invalidDocumentError = "document is not a valid JSON or YAML object"
+
+// ValidateOASURLParams represents the parameters for the validateOASURL tool.
+// The `url` parameter is required.
+type ValidateOASURLParams struct {
+	URL string `json:"url" jsonschema:"required" jsonschema_description:"URL of the OpenAPI document."`
+}
+
+// ValidateAPISpecificationParams represents the parameters for the validateAPISpecification tool.
+// The `id` parameter is required.
+type ValidateAPISpecificationParams struct {
+	ID string `json:"id" jsonschema:"required" jsonschema_description:"ID of the API."`
+}
+
+// OASValidationReport represents the result of validating an OpenAPI document.
+type OASValidationReport struct {
+	ID             string   `json:"id,omitempty"`
+	URL            string   `json:"url"`
+	Valid          bool     `json:"valid"`
+	SpecVersion    string   `json:"spec_version,omitempty"`
+	Title          string   `json:"title,omitempty"`
+	OperationCount int      `json:"operation_count"`
+	Paths          []string `json:"paths"`
+	Errors         []string `json:"errors,omitempty"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+// createValidateOASURLTool creates a tool for validating an OpenAPI document by
+// URL, without looking it up in the register.
+func createValidateOASURLTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ValidateOASURLParams]{
+		Name: "validate_oas_url",
+		Description: "Validate an OpenAPI (3.x) or Swagger (2.0) document by URL, independent of the register. " +
+			"Returns a validation report with errors, warnings and the paths it declares. Only public http(s) " +
+			"URLs (and redirects to them) are allowed.",
+		HandleFunc: func(ctx context.Context, params ValidateOASURLParams) *mcp.CallToolResult {
+			ctx, cancel := context.WithTimeout(ctx, oasURLTimeout)
+			defer cancel()
+
+			body, _, err := readSpec(ctx, params.URL)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching OpenAPI specification: %v", err)
+			}
+
+			report := OASValidationReport{Paths: []string{}}
+			if spec, err := decodeSpec(body); err != nil {
+				report.Errors = []string{invalidDocumentError}
+			} else {
+				report = validateSpec(spec)
+			}
+			report.URL = params.URL
+
+			result, err := marshalResult(report, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// createValidateAPISpecificationTool creates a tool for validating the OpenAPI
+// specification of an API.
+func createValidateAPISpecificationTool() mcp.Tool {
+	return createTool(mcp.ToolDef[ValidateAPISpecificationParams]{
+		Name: "validate_api_specification",
+		Description: "Validate the OpenAPI specification of an API by ID: checks that it's valid JSON or YAML, " +
+			"declares a supported `openapi` (3.x) or `swagger` (2.0) version, and has `info` and `paths`. " +
+			"Returns a validation report with errors, warnings and the paths it declares.",
+		HandleFunc: func(ctx context.Context, params ValidateAPISpecificationParams) *mcp.CallToolResult {
+			api, err := fetchAPI(ctx, params.ID)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching API: %v", err)
+			}
+
+			specURL := specificationURL(api)
+			if specURL == "" {
+				return newToolCallErrorResult("API with ID %v has no OpenAPI specification URL", params.ID)
+			}
+
+			body, _, err := readSpec(ctx, specURL)
+			if err != nil {
+				return newToolCallErrorResult("Error fetching OpenAPI specification: %v", err)
+			}
+
+			report := OASValidationReport{Paths: []string{}}
+			if spec, err := decodeSpec(body); err != nil {
+				report.Errors = []string{err.Error()}
+			} else {
+				report = validateSpec(spec)
+			}
+			report.ID = params.ID
+			report.URL = specURL
+
+			result, err := marshalResult(report, prettyOutput)
+			if err != nil {
+				return newToolCallErrorResult("Error formatting response: %v", err)
+			}
+
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.TextContent{
+						Text: string(result),
+					},
+				},
+			}
+		},
+	})
+}
+
+// validateSpec checks the structure of a decoded OpenAPI 3.x or Swagger 2.0
+// document. It checks the document's essentials (version, info and paths),
+// not its full conformance to the JSON schema of the specification.
+func validateSpec(spec map[string]any) OASValidationReport {
+	report := OASValidationReport{Paths: []string{}}
+
+	summary := summarizeOperations(spec)
+	report.SpecVersion = summary.SpecVersion
+	report.OperationCount = summary.OperationCount
+
+	switch {
+	case report.SpecVersion == "":
+		report.Errors = append(report.Errors, "missing `openapi` or `swagger` version field")
+	case spec["openapi"] != nil && !strings.HasPrefix(report.SpecVersion, "3."):
+		report.Errors = append(report.Errors, fmt.Sprintf("unsupported OpenAPI version %q", report.SpecVersion))
+	case spec["swagger"] != nil && report.SpecVersion != "2.0":
+		report.Errors = append(report.Errors, fmt.Sprintf("unsupported Swagger version %q", report.SpecVersion))
+	}
+
+	info, ok := spec["info"].(map[string]any)
+	if !ok {
+		report.Errors = append(report.Errors, "missing `info` object")
+	} else {
+		report.Title = recordString(info, "title")
+		if report.Title == "" {
+			report.Errors = append(report.Errors, "missing `info.title`")
+		}
+		if recordString(info, "version") == "" {
+			report.Errors = append(report.Errors, "missing `info.version`")
+		}
+	}
+
+	paths, ok := spec["paths"].(map[string]any)
+	if !ok {
+		// OpenAPI 3.1 allows documents with only webhooks or components.
+		if strings.HasPrefix(report.SpecVersion, "3.1") && (spec["webhooks"] != nil || spec["components"] != nil) {
+			report.Warnings = append(report.Warnings, "document declares no `paths`")
+		} else {
+			report.Errors = append(report.Errors, "missing `paths` object")
+		}
+	}
+
+	operationIDs := make(map[string]bool)
+	for path, v := range paths {
+		report.Paths = append(report.Paths, path)
+		if !strings.HasPrefix(path, "/") {
+			report.Errors = append(report.Errors, fmt.Sprintf("path %q must start with a slash", path))
+		}
+
+		pathItem, ok := v.(map[string]any)
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("path %q is not an object", path))
+			continue
+		}
+		for _, method := range oasMethods {
+			op, ok := pathItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			if id := recordString(op, "operationId"); id != "" {
+				if operationIDs[id] {
+					report.Errors = append(report.Errors, fmt.Sprintf("duplicate operationId %q", id))
+				}
+				operationIDs[id] = true
+			}
+			if _, ok := op["responses"].(map[string]any); !ok {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("operation %v %v declares no responses", strings.ToUpper(method), path))
+			}
+		}
+	}
+
+	slices.Sort(report.Paths)
+	slices.Sort(report.Errors)
+	slices.Sort(report.Warnings)
+	report.Valid = len(report.Errors) == 0
+
+	return report
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newSpecServer starts a test server serving documents by path.
+func newSpecServer(t *testing.T, docs map[string]string) string {
+	t.Helper()
+
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch doc, ok := docs[r.URL.Path]; {
+		case r.URL.Path == "/redirect":
+			http.Redirect(w, r, "http://10.0.0.1/openapi.json", http.StatusFound)
+		case ok:
+			w.Write([]byte(doc))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return srv.URL
+}
+
+func TestValidateOASURL(t *testing.T) {
+	allowPrivateIPs(t)
+	srvURL := newSpecServer(t, map[string]string{
+		"/valid.yaml":   "openapi: 3.0.3\ninfo:\n  title: Test\n  version: '1'\npaths:\n  /b: {get: {}}\n  /a: {post: {}}\n",
+		"/invalid.json": `{"openapi": "3.0.3"}`,
+		"/secret.txt":   "root:x:0:0:root:/root:/bin/bash\n",
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		var got OASValidationReport
+		decodeResult(t, callTool(t, createValidateOASURLTool(), `{"url":"`+srvURL+`/valid.yaml"}`), &got)
+		if !got.Valid || got.Title != "Test" || got.OperationCount != 2 || !slices.Equal(got.Paths, []string{"/a", "/b"}) {
+			t.Errorf("got %+v, want a valid report with paths /a and /b", got)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var got OASValidationReport
+		decodeResult(t, callTool(t, createValidateOASURLTool(), `{"url":"`+srvURL+`/invalid.json"}`), &got)
+		if got.Valid || len(got.Errors) == 0 {
+			t.Errorf("got %+v, want an invalid report with errors", got)
+		}
+	})
+
+	t.Run("not a document", func(t *testing.T) {
+		res := callTool(t, createValidateOASURLTool(), `{"url":"`+srvURL+`/secret.txt"}`)
+
+		var got OASValidationReport
+		decodeResult(t, res, &got)
+		if got.Valid || !slices.Equal(got.Errors, []string{invalidDocumentError}) {
+			t.Errorf("errors = %q, want only %q", got.Errors, invalidDocumentError)
+		}
+		if text := resultText(t, res); strings.Contains(text, "root:") {
+			t.Errorf("result leaks the document: %v", text)
+		}
+	})
+
+	t.Run("redirect to private address", func(t *testing.T) {
+		res := callTool(t, createValidateOASURLTool(), `{"url":"`+srvURL+`/redirect"}`)
+		expectError(t, res, `host "10.0.0.1" is not allowed`)
+	})
+
+	t.Run("host not allowed", func(t *testing.T) {
+		setAllowedHosts(t, "overheid.nl")
+
+		res := callTool(t, createValidateOASURLTool(), `{"url":"`+srvURL+`/valid.yaml"}`)
+		expectError(t, res, "not in the allowed hosts")
+	})
+}
+
+func TestValidateOASURLDisallowedHost(t *testing.T) {
+	srvURL := newSpecServer(t, map[string]string{
+		"/valid.yaml": "openapi: 3.0.3\ninfo: {title: Test, version: '1'}\npaths: {}\n",
+	})
+
+	for _, u := range []string{
+		srvURL + "/valid.yaml",
+		"http://localhost/openapi.json",
+		"http://169.254.169.254/latest/meta-data/",
+		"file:///etc/passwd",
+	} {
+		res := callTool(t, createValidateOASURLTool(), `{"url":"`+u+`"}`)
+		expectError(t, res, "not allowed")
+	}
+}
+
+func TestValidateAPISpecification(t *testing.T) {
+	allowPrivateIPs(t)
+
+	valid, err := os.ReadFile(filepath.Join("testdata", "oas", "openapi3.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := map[string]string{
+		"valid":     string(valid),
+		"malformed": `{"openapi": "3.0.3", "info": {`,
+		"not-oas":   `{"type": "FeatureCollection", "features": []}`,
+	}
+
+	var srvURL string
+	srv := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id, ok := strings.CutPrefix(r.URL.Path, "/apis/"); ok {
+			if _, ok := specs[id]; !ok {
+				fixture{body: `{"id":"` + id + `","environments":[]}`}.serve(w)
+				return
+			}
+			fixture{body: `{"id":"` + id + `","environments":[{"name":"production","specification_url":"` + srvURL + `/specs/` + id + `"}]}`}.serve(w)
+			return
+		}
+		if spec, ok := specs[strings.TrimPrefix(r.URL.Path, "/specs/")]; ok {
+			w.Write([]byte(spec))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	srvURL = srv.URL
+
+	t.Run("valid", func(t *testing.T) {
+		var got OASValidationReport
+		decodeResult(t, callTool(t, createValidateAPISpecificationTool(), `{"id":"valid"}`), &got)
+		if !got.Valid || len(got.Errors) > 0 || got.SpecVersion != "3.0.3" || got.OperationCount != 5 {
+			t.Errorf("got %+v, want a valid OpenAPI 3.0.3 report with 5 operations", got)
+		}
+		if got.ID != "valid" || got.URL != srvURL+"/specs/valid" {
+			t.Errorf("id = %q, url = %q; want the API and its specification URL", got.ID, got.URL)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		var got OASValidationReport
+		decodeResult(t, callTool(t, createValidateAPISpecificationTool(), `{"id":"malformed"}`), &got)
+		if got.Valid || len(got.Errors) != 1 || !strings.HasPrefix(got.Errors[0], "invalid JSON") {
+			t.Errorf("errors = %q, want an invalid JSON error", got.Errors)
+		}
+	})
+
+	t.Run("not an OpenAPI document", func(t *testing.T) {
+		var got OASValidationReport
+		decodeResult(t, callTool(t, createValidateAPISpecificationTool(), `{"id":"not-oas"}`), &got)
+		want := []string{"missing `info` object", "missing `openapi` or `swagger` version field", "missing `paths` object"}
+		if got.Valid || !slices.Equal(got.Errors, want) {
+			t.Errorf("errors = %q, want %q", got.Errors, want)
+		}
+	})
+
+	t.Run("no specification", func(t *testing.T) {
+		res := callTool(t, createValidateAPISpecificationTool(), `{"id":"none"}`)
+		expectError(t, res, "has no OpenAPI specification URL")
+	})
+}
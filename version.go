@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// Build information, set at build time via `-ldflags -X` (GoReleaser sets
+// these by default).
+var (
+	version = "dev"
+	commit  = "unknown"
+	date    = "unknown"
+)
+
+// versionString returns a line describing the build of the server.
+func versionString() string {
+	v, c, d := buildInfo()
+	return fmt.Sprintf("mcp-developer-overheid-api-register %v (commit %v, built %v)", v, c, d)
+}
+
+// buildInfo returns the version, commit and build date of the server. For
+// builds without stamped version information (e.g. `go install`), the module
+// version and VCS revision embedded by the Go toolchain are used, if any.
+func buildInfo() (v, c, d string) {
+	v, c, d = version, commit, date
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch {
+			case s.Key == "vcs.revision" && c == "unknown":
+				c = s.Value
+			case s.Key == "vcs.time" && d == "unknown":
+				d = s.Value
+			}
+		}
+	}
+
+	return v, c, d
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestVersionString(t *testing.T) {
+	setBuildInfo := func(t *testing.T, v, c, d string) {
+		t.Helper()
+
+		oldVersion, oldCommit, oldDate := version, commit, date
+		t.Cleanup(func() { version, commit, date = oldVersion, oldCommit, oldDate })
+		version, commit, date = v, c, d
+	}
+
+	t.Run("stamped", func(t *testing.T) {
+		setBuildInfo(t, "v1.2.3", "abc1234", "2025-03-01T12:00:00Z")
+
+		want := "mcp-developer-overheid-api-register v1.2.3 (commit abc1234, built 2025-03-01T12:00:00Z)"
+		if got := versionString(); got != want {
+			t.Errorf("versionString() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("not stamped", func(t *testing.T) {
+		setBuildInfo(t, "dev", "unknown", "unknown")
+
+		// Test binaries have no module version or VCS information, so the
+		// defaults remain, but the format is the same.
+		want := regexp.MustCompile(`^mcp-developer-overheid-api-register \S+ \(commit \S+, built \S+\)$`)
+		if got := versionString(); !want.MatchString(got) {
+			t.Errorf("versionString() = %q, want it to match %v", got, want)
+		}
+	})
+}